@@ -0,0 +1,183 @@
+package chainlog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+// key prefixes used by deploy, deploy-oracle.sh, dss-deploy and the chainlog
+const (
+	OsmPrefix    = "PIP_"
+	MedianPrefix = "MEDIAN_"
+)
+
+// errors
+var (
+	errLoad    = errors.New("failed to load addresses")
+	errGetCode = errors.New("failed to get code")
+)
+
+func errMissingKey(key string, path string, similar []string) error {
+	if len(similar) == 0 {
+		return fmt.Errorf("%s has no %s", path, key)
+	}
+	return fmt.Errorf("%s has no %s (found %s)", path, key, strings.Join(similar, ", "))
+}
+
+func errMalformed(key string, value string) error {
+	return fmt.Errorf("%s is not a valid address: %q", key, value)
+}
+
+func errNoCode(key string, address common.Address) error {
+	return fmt.Errorf("%s (%s) has no code on chain", key, address.Hex())
+}
+
+// Addresses is a validated set of named contract addresses.
+type Addresses struct {
+	path    string
+	entries map[string]common.Address
+}
+
+// Load reads an address file. The flat object written by deploy-oracle.sh and dss-deploy,
+// as well as the same object nested under "addresses" or "contracts" are accepted.
+// Values not starting with 0x (e.g. a version) are ignored, the others must be well formed addresses.
+func Load(path string) (*Addresses, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, util.ChainError(errLoad, err)
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, util.ChainError(errLoad, err)
+	}
+	for _, nested := range []string{"addresses", "contracts"} {
+		if inner, ok := doc[nested]; ok {
+			var entries map[string]json.RawMessage
+			if err := json.Unmarshal(inner, &entries); err == nil {
+				doc = entries
+				break
+			}
+		}
+	}
+
+	addresses := &Addresses{path: path, entries: make(map[string]common.Address, len(doc))}
+	for key, value := range doc {
+		var hex string
+		if err := json.Unmarshal(value, &hex); err != nil || !strings.HasPrefix(hex, "0x") {
+			continue
+		}
+		if !common.IsHexAddress(hex) {
+			return nil, util.ChainError(errLoad, errMalformed(key, hex))
+		}
+		addresses.entries[key] = common.HexToAddress(hex)
+	}
+
+	return addresses, nil
+}
+
+// Get returns the address named key.
+func (addresses *Addresses) Get(key string) (common.Address, error) {
+	address, ok := addresses.entries[key]
+	if !ok {
+		return common.Address{}, errMissingKey(key, addresses.path, addresses.similar(key))
+	}
+
+	return address, nil
+}
+
+// Has reports whether key is present.
+func (addresses *Addresses) Has(key string) bool {
+	_, ok := addresses.entries[key]
+	return ok
+}
+
+// Osm returns PIP_<token>.
+func (addresses *Addresses) Osm(token string) (common.Address, error) {
+	return addresses.Get(OsmPrefix + token)
+}
+
+// Median returns MEDIAN_<token>.
+func (addresses *Addresses) Median(token string) (common.Address, error) {
+	return addresses.Get(MedianPrefix + token)
+}
+
+// Keys returns every key in order.
+func (addresses *Addresses) Keys() []string {
+	keys := make([]string, 0, len(addresses.entries))
+	for key := range addresses.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// similar lists keys sharing the prefix of key, to point out typos in the token name.
+func (addresses *Addresses) similar(key string) []string {
+	prefix := key
+	if i := strings.Index(key, "_"); 0 <= i {
+		prefix = key[:i+1]
+	}
+
+	var similar []string
+	for _, candidate := range addresses.Keys() {
+		if strings.HasPrefix(candidate, prefix) {
+			similar = append(similar, candidate)
+		}
+	}
+
+	return similar
+}
+
+// CodeReader is the part of a chain client needed by Verify.
+type CodeReader interface {
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// Verify checks that every address holds a contract. Only keys are checked when given.
+func (addresses *Addresses) Verify(ctx context.Context, reader CodeReader, keys ...string) error {
+	if len(keys) == 0 {
+		keys = addresses.Keys()
+	}
+
+	for _, key := range keys {
+		address, err := addresses.Get(key)
+		if err != nil {
+			return err
+		}
+		code, err := reader.CodeAt(ctx, address, nil)
+		if err != nil {
+			return util.ChainError(errGetCode, err)
+		}
+		if len(code) == 0 {
+			return errNoCode(key, address)
+		}
+	}
+
+	return nil
+}
+
+// Write stores entries in the flat format of deploy-oracle.sh.
+func Write(path string, entries map[string]common.Address) error {
+	flat := make(map[string]string, len(entries))
+	for key, address := range entries {
+		flat[key] = address.Hex()
+	}
+
+	raw, err := json.MarshalIndent(flat, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(raw, '\n'), 0644)
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/tgfukuda/test-feed/chainlog"
+	"github.com/tgfukuda/test-feed/transact"
+)
+
+type AdminOption struct {
+	addresses string
+}
+
+// adminAction sends one administrative transaction with the connected oracle.
+type adminAction func(ctx context.Context, oracle *transact.Oracle, args []string) (*transact.TxResult, error)
+
+func newAdminCommand(use string, short string, subOpts *AdminOption) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  ``,
+	}
+	cmd.PersistentFlags().StringVarP(
+		&subOpts.addresses,
+		"addresses",
+		"a",
+		"addresses.json",
+		"addresses file holding the PIP_<token> and MEDIAN_<token> keys",
+	)
+
+	return cmd
+}
+
+func adminCommand(opts *Options, subOpts *AdminOption, use string, short string, args cobra.PositionalArgs, action adminAction) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Args:  args,
+		Short: short,
+		Long:  ``,
+		RunE: func(_ *cobra.Command, args []string) (err error) {
+			addresses, err := chainlog.Load(subOpts.addresses)
+			if err != nil {
+				return err
+			}
+
+			privKey, err := transact.GetPrivFromFile(opts.keystore, opts.password)
+			if err != nil {
+				return err
+			}
+
+			logger := opts.logger
+
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, os.Interrupt)
+			defer stop()
+
+			backend, err := opts.dial(logger)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				logger.Debug("disconnecting rpc")
+				backend.Close()
+			}()
+
+			contracts, err := getContracts(ctx, addresses, backend, chainlog.OsmPrefix+opts.name, chainlog.MedianPrefix+opts.name)
+			if err != nil {
+				return err
+			}
+
+			oracle, err := transact.New(ctx, backend, privKey, contracts, opts.osm, opts.median, logger)
+			if err != nil {
+				return err
+			}
+			opts.configure(oracle)
+
+			_, err = action(ctx, oracle, args)
+
+			return err
+		},
+	}
+}
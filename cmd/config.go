@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tgfukuda/test-feed/chainlog"
+	"github.com/tgfukuda/test-feed/util"
+	"gopkg.in/yaml.v3"
+)
+
+// FeedConfig lists the pairs fed by a single `feed` process.
+//
+//	addresses: ./addresses.json
+//	pairs:
+//	  - name: ETHJPY
+//	    address: PIP_ETH
+//	    wat: ethjpy
+//	    source: static:100000
+//	    interval: 1h
+//	    spread: 0.5
+//	    heartbeat: 6h
+//	    signers: [./keystore]
+type FeedConfig struct {
+	Addresses string       `yaml:"addresses"`
+	Pairs     []PairConfig `yaml:"pairs"`
+}
+
+// PairConfig describes one Median/OSM pair. Empty fields fall back to the command line flags.
+type PairConfig struct {
+	Name        string        `yaml:"name"`
+	Address     string        `yaml:"address"` // key of the OSM in the addresses file
+	Median      string        `yaml:"median"`  // key of the Median. MEDIAN_<token> for PIP_<token> if empty
+	Wat         string        `yaml:"wat"`
+	Source      string        `yaml:"source"`
+	JsonPath    string        `yaml:"json_path"`
+	Interval    time.Duration `yaml:"interval"`
+	EveryBlocks uint64        `yaml:"every_blocks"`
+	Spread      float64       `yaml:"spread"`
+	Heartbeat   time.Duration `yaml:"heartbeat"`
+	PokeOsm     *bool         `yaml:"poke_osm"`
+	Keystore    string        `yaml:"keystore"` // sender. --keystore if empty
+	Signers     []string      `yaml:"signers"`
+}
+
+var (
+	errLoadConfig = errors.New("failed to load config")
+	errNoPairs    = errors.New("config has no pairs")
+)
+
+func errDuplicatePair(name string) error {
+	return fmt.Errorf("pair %s is configured twice", name)
+}
+
+func errPairField(index int, field string) error {
+	return fmt.Errorf("pair #%d has no %s", index, field)
+}
+
+func errInterval(name string, interval time.Duration) error {
+	return fmt.Errorf("pair %s needs a positive interval or every_blocks, got %s", name, interval)
+}
+
+func loadFeedConfig(path string) (*FeedConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, util.ChainError(errLoadConfig, err)
+	}
+
+	var config FeedConfig
+	if err := yaml.Unmarshal(raw, &config); err != nil {
+		return nil, util.ChainError(errLoadConfig, err)
+	}
+
+	if len(config.Pairs) == 0 {
+		return nil, util.ChainError(errLoadConfig, errNoPairs)
+	}
+
+	names := make(map[string]bool, len(config.Pairs))
+	for i, pair := range config.Pairs {
+		if pair.Address == "" && pair.Median == "" {
+			return nil, util.ChainError(errLoadConfig, errPairField(i, "address"))
+		}
+		if pair.Median == "" && strings.HasPrefix(pair.Address, chainlog.OsmPrefix) {
+			config.Pairs[i].Median = chainlog.MedianPrefix + strings.TrimPrefix(pair.Address, chainlog.OsmPrefix)
+		}
+		if pair.Name == "" {
+			config.Pairs[i].Name = pair.Address
+		}
+		if config.Pairs[i].Name == "" {
+			config.Pairs[i].Name = pair.Median
+		}
+		if pair.Interval < 0 {
+			return nil, util.ChainError(errLoadConfig, errInterval(config.Pairs[i].Name, pair.Interval))
+		}
+		if names[config.Pairs[i].Name] {
+			return nil, util.ChainError(errLoadConfig, errDuplicatePair(config.Pairs[i].Name))
+		}
+		names[config.Pairs[i].Name] = true
+	}
+
+	return &config, nil
+}
+
+// withDefaults fills the fields left empty in the config file from the command line.
+// A pair fed on a timer must end up with a positive interval.
+func (pair PairConfig) withDefaults(opts *Options, subOpts *FeedOption) (PairConfig, error) {
+	if pair.Source == "" {
+		pair.Source, pair.JsonPath = subOpts.source, subOpts.jsonPath
+	}
+	if pair.Wat == "" {
+		pair.Wat = subOpts.wat
+	}
+	if pair.Interval == 0 {
+		pair.Interval = time.Duration(subOpts.interval) * time.Second
+	}
+	if pair.EveryBlocks == 0 {
+		pair.EveryBlocks = subOpts.blocks
+	}
+	if pair.Spread == 0 {
+		pair.Spread = subOpts.spread
+	}
+	if pair.Heartbeat == 0 {
+		pair.Heartbeat = subOpts.heartbeat
+	}
+	if len(pair.Signers) == 0 {
+		pair.Signers = subOpts.signers
+	}
+	if pair.PokeOsm == nil {
+		pair.PokeOsm = &subOpts.pokeOsm
+	}
+	if pair.Keystore == "" {
+		pair.Keystore = opts.keystore
+	}
+	if pair.EveryBlocks == 0 && pair.Interval <= 0 {
+		return pair, errInterval(pair.Name, pair.Interval)
+	}
+
+	return pair, nil
+}
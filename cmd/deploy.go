@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/tgfukuda/test-feed/chainlog"
+	"github.com/tgfukuda/test-feed/transact"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+var errWriteAddresses = errors.New("failed to write addresses")
+
+func errInvalidAddress(address string) error {
+	return fmt.Errorf("%q is not an address", address)
+}
+
+func errTokenMismatch(token string, wat string) error {
+	return fmt.Errorf("the embedded Median is for %s, not %s. pass the token of %s with -t or embed the Median of %s with deploy-oracle.sh", wat, token, wat, token)
+}
+
+func errInvalidBar(bar uint64, feeders int) error {
+	return fmt.Errorf("bar must be odd and at most the number of feeders (%d), got %d", feeders, bar)
+}
+
+type DeployOption struct {
+	feeders []string
+	bar     uint64
+	readers []string
+	hop     time.Duration
+	output  string
+}
+
+func newDeployCommand(opts *Options) *cobra.Command {
+	subOpts := DeployOption{}
+	cmd := deployCommand(opts, &subOpts)
+	cmd.Flags().StringSliceVar(
+		&subOpts.feeders,
+		"feeders",
+		nil,
+		"addresses lifted to sign observations. the sender if empty",
+	)
+	cmd.Flags().Uint64Var(
+		&subOpts.bar,
+		"bar",
+		1,
+		"number of observations in each poke. must be odd",
+	)
+	cmd.Flags().StringSliceVar(
+		&subOpts.readers,
+		"readers",
+		nil,
+		"addresses kissed on the Median and OSM. the sender if empty",
+	)
+	cmd.Flags().DurationVar(
+		&subOpts.hop,
+		"hop",
+		time.Hour,
+		"delay between OSM pokes",
+	)
+	cmd.Flags().StringVarP(
+		&subOpts.output,
+		"output",
+		"o",
+		"addresses.json",
+		"addresses file to write. other entries in an existing file are kept",
+	)
+
+	return cmd
+}
+
+func deployCommand(opts *Options, subOpts *DeployOption) *cobra.Command {
+	return &cobra.Command{
+		Use:   "deploy",
+		Args:  cobra.NoArgs,
+		Short: "deploy and set up a Median and an OSM",
+		Long:  ``,
+		RunE: func(_ *cobra.Command, _ []string) (err error) {
+			privKey, err := transact.GetPrivFromFile(opts.keystore, opts.password)
+			if err != nil {
+				return err
+			}
+
+			feeders, err := parseAddresses(subOpts.feeders)
+			if err != nil {
+				return err
+			}
+			readers, err := parseAddresses(subOpts.readers)
+			if err != nil {
+				return err
+			}
+			// the wat is fixed by the Median built, so that another token would be signed for the wrong pair
+			wat, err := transact.EmbeddedWat()
+			if err != nil {
+				return err
+			}
+			if !strings.HasPrefix(wat, opts.name) {
+				return errTokenMismatch(opts.name, wat)
+			}
+
+			logger := opts.logger
+
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, os.Interrupt)
+			defer stop()
+
+			backend, err := opts.dial(logger)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				logger.Debug("disconnecting rpc")
+				backend.Close()
+			}()
+
+			deployer, err := transact.NewAccount(backend, privKey, logger)
+			if err != nil {
+				return err
+			}
+			opts.configure(deployer)
+			nonces := transact.NewNonceManager()
+			deployer.SetNonceManager(nonces)
+
+			if len(feeders) == 0 {
+				feeders = []common.Address{deployer.From()}
+			}
+			if len(readers) == 0 {
+				readers = []common.Address{deployer.From()}
+			}
+			if subOpts.bar%2 == 0 || uint64(len(feeders)) < subOpts.bar {
+				return errInvalidBar(subOpts.bar, len(feeders))
+			}
+
+			median, _, err := deployer.DeployMedian(ctx)
+			if err != nil {
+				return err
+			}
+			osm, _, err := deployer.DeployOSM(ctx, median)
+			if err != nil {
+				return err
+			}
+
+			oracle, err := transact.New(ctx, backend, privKey, transact.Contracts{Osm: osm, Median: median}, "", "", logger)
+			if err != nil {
+				return err
+			}
+			opts.configure(oracle)
+			oracle.SetNonceManager(nonces)
+
+			if _, err := oracle.Lift(ctx, feeders...); err != nil {
+				return err
+			}
+			if _, err := oracle.SetBar(ctx, subOpts.bar); err != nil {
+				return err
+			}
+			if _, err := oracle.KissMedian(ctx, append([]common.Address{osm}, readers...)...); err != nil {
+				return err
+			}
+			if _, err := oracle.KissOsm(ctx, readers...); err != nil {
+				return err
+			}
+			if subOpts.hop != time.Hour {
+				if _, err := oracle.SetHop(ctx, subOpts.hop); err != nil {
+					return err
+				}
+			}
+
+			if err := writeAddresses(subOpts.output, map[string]common.Address{
+				chainlog.OsmPrefix + opts.name:    osm,
+				chainlog.MedianPrefix + opts.name: median,
+			}); err != nil {
+				return err
+			}
+
+			logger.Info("deployed", chainlog.MedianPrefix+opts.name, median, chainlog.OsmPrefix+opts.name, osm, "wat", oracle.Wat(), "output", subOpts.output)
+
+			return nil
+		},
+	}
+}
+
+// parseAddresses checks that each of hexes is a 0x prefixed address.
+func parseAddresses(hexes []string) ([]common.Address, error) {
+	addresses := make([]common.Address, len(hexes))
+	for i, hex := range hexes {
+		if !common.IsHexAddress(hex) || len(hex) != 2*common.AddressLength+2 {
+			return nil, errInvalidAddress(hex)
+		}
+		addresses[i] = common.HexToAddress(hex)
+	}
+
+	return addresses, nil
+}
+
+// writeAddresses adds entries to the addresses file at path, creating it if missing.
+func writeAddresses(path string, entries map[string]common.Address) error {
+	merged := make(map[string]common.Address)
+	if _, err := os.Stat(path); err == nil {
+		existing, err := chainlog.Load(path)
+		if err != nil {
+			return util.ChainError(errWriteAddresses, err)
+		}
+		for _, key := range existing.Keys() {
+			merged[key], _ = existing.Get(key)
+		}
+	}
+	for key, address := range entries {
+		merged[key] = address
+	}
+
+	if err := chainlog.Write(path, merged); err != nil {
+		return util.ChainError(errWriteAddresses, err)
+	}
+
+	return nil
+}
@@ -1,22 +1,40 @@
 package cmd
 
 import (
-	"encoding/json"
-	"log"
-	"math/big"
+	"context"
+	"errors"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/spf13/cobra"
+	"github.com/tgfukuda/test-feed/chainlog"
+	"github.com/tgfukuda/test-feed/metrics"
+	"github.com/tgfukuda/test-feed/policy"
+	"github.com/tgfukuda/test-feed/source"
 	"github.com/tgfukuda/test-feed/transact"
 	"github.com/tgfukuda/test-feed/util"
 )
 
+var errNoAddresses = errors.New("addresses file is required as an argument or in the config")
+
 type FeedOption struct {
-	interval uint16
+	interval  uint16
+	source    string
+	jsonPath  string
+	signers   []string
+	wat       string
+	spread    float64
+	heartbeat time.Duration
+	pokeOsm   bool
+	blocks    uint64
+	config    string
+	metrics   string
+	status    string
 }
 
 func newFeedCommand(opts *Options) *cobra.Command {
@@ -29,79 +47,270 @@ func newFeedCommand(opts *Options) *cobra.Command {
 		3600,
 		"interval of each transaction",
 	)
+	cmd.Flags().StringVarP(
+		&subOpts.source,
+		"source",
+		"s",
+		"static:100000",
+		"price source. static:<price>, file:<path>, http(s)://<url> or stdin",
+	)
+	cmd.Flags().StringVar(
+		&subOpts.jsonPath,
+		"json-path",
+		"",
+		"dot separated path to the price in json documents (e.g. data.0.price)",
+	)
+	cmd.Flags().StringSliceVar(
+		&subOpts.signers,
+		"signers",
+		nil,
+		"keystore files or directories signing observations. the sender key is used if empty",
+	)
+	cmd.Flags().StringVar(
+		&subOpts.wat,
+		"wat",
+		"",
+		"asset label signed with each price. read from median if empty",
+	)
+	cmd.Flags().Float64Var(
+		&subOpts.spread,
+		"spread",
+		0,
+		"poke only when the price deviates from median by more than this percent. 0 disables",
+	)
+	cmd.Flags().DurationVar(
+		&subOpts.heartbeat,
+		"heartbeat",
+		0,
+		"poke when the median value is older than this regardless of spread. 0 disables",
+	)
+	cmd.Flags().BoolVar(
+		&subOpts.pokeOsm,
+		"poke-osm",
+		true,
+		"poke osm as soon as each hop elapses",
+	)
+	cmd.Flags().Uint64Var(
+		&subOpts.blocks,
+		"every-blocks",
+		0,
+		"feed every n blocks from a newHeads subscription instead of --interval. needs a ws or ipc endpoint",
+	)
+	cmd.Flags().StringVarP(
+		&subOpts.config,
+		"config",
+		"c",
+		"",
+		"yaml file listing the pairs to feed. flags are used for the fields left empty",
+	)
+	cmd.Flags().StringVar(
+		&subOpts.metrics,
+		"metrics-addr",
+		"",
+		"serve prometheus metrics at http://<addr>/metrics (e.g. :9100). disabled if empty",
+	)
+	cmd.Flags().StringVar(
+		&subOpts.status,
+		"status-addr",
+		"",
+		"serve the json status of the pairs at http://<addr>/pairs (e.g. 127.0.0.1:8080). disabled if empty",
+	)
 
 	return cmd
 }
 
 func feedCommand(opts *Options, subOpts *FeedOption) *cobra.Command {
 	return &cobra.Command{
-		Use:   "feed",
-		Args:  cobra.ExactArgs(1),
+		Use:   "feed [addresses.json]",
+		Args:  cobra.MaximumNArgs(1),
 		Short: "running feed client",
 		Long:  ``,
 		RunE: func(_ *cobra.Command, args []string) (err error) {
-			addressPath := args[0]
-			addresses, err := getAddresses(addressPath)
-			if err != nil {
-				return err
+			pairs := []PairConfig{{
+				Name:    opts.name,
+				Address: chainlog.OsmPrefix + opts.name,
+				Median:  chainlog.MedianPrefix + opts.name,
+			}}
+			addressPath := ""
+			if subOpts.config != "" {
+				config, err := loadFeedConfig(subOpts.config)
+				if err != nil {
+					return err
+				}
+				pairs, addressPath = config.Pairs, config.Addresses
+			}
+			if len(args) == 1 {
+				addressPath = args[0]
+			}
+			if addressPath == "" {
+				return errNoAddresses
+			}
+			for i, pair := range pairs {
+				if pairs[i], err = pair.withDefaults(opts, subOpts); err != nil {
+					return err
+				}
 			}
 
-			privKey, err := transact.GetPrivFromFile(opts.keystore, opts.password)
+			addresses, err := chainlog.Load(addressPath)
 			if err != nil {
 				return err
 			}
 
-			osm, ok := addresses[OraclePrefix+opts.name].(string)
-			if !ok {
-				return util.ErrCast
-			}
+			logger := opts.logger
 
-			logger := log.Default()
+			// a signal cancels the in-flight pokes as well as the loops
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, os.Interrupt)
+			defer stop()
 
-			oracle, err := transact.New(opts.endpoint, privKey, osm, opts.osm, opts.median, logger)
+			backend, err := opts.dial(logger)
 			if err != nil {
 				return err
 			}
+			defer func() {
+				logger.Debug("disconnecting rpc")
+				backend.Close()
+			}()
 
-			trap := make(chan os.Signal, 1)
-			signal.Notify(trap, syscall.SIGTERM, syscall.SIGINT, os.Interrupt)
+			// pairs sent from the same account must not race for nonces
+			nonces := make(map[common.Address]*transact.NonceManager)
 
-			quit := make(chan bool, 1)
+			var registry *metrics.Metrics
+			if subOpts.metrics != "" {
+				registry = metrics.New()
+				go func() {
+					if err := registry.Serve(ctx, subOpts.metrics); err != nil {
+						logger.Error("metrics server stopped", "err", err)
+					}
+				}()
+				logger.Info("serving metrics", "addr", subOpts.metrics)
+			}
 
-			feed := func() {
-				tx, err := oracle.Poke(func(ts time.Time) *big.Int {
-					price, _ := math.ParseBig256("100000000000000000000000")
-					return price
-				})
-				if tx != nil {
-					logger.Printf("[INFO] sent transaction %s", tx.Hash().Hex())
-				}
+			feeders := make([]*feeder, 0, len(pairs))
+			for _, pair := range pairs {
+				f, err := newFeeder(ctx, opts, pair, addresses, backend, nonces, logger.New("pair", pair.Name))
 				if err != nil {
-					logger.Println(err)
+					return util.ChainError(errors.New("failed to set up "+pair.Name), err)
+				}
+				defer f.src.Close()
+				if registry != nil {
+					f.metrics = registry.Pair(f.name)
 				}
-				s, _ := json.MarshalIndent(tx, "", "\t")
-				logger.Printf("%s\n", s)
+				feeders = append(feeders, f)
 			}
 
-			feed()
-
-			go func() {
-				defer close(quit)
-				for {
-					select {
-					case <-trap:
-						logger.Printf("closing session...\n")
-						quit <- true
-						return
-					case <-time.After(time.Duration(subOpts.interval) * time.Second):
-						feed()
+			if subOpts.status != "" {
+				go func() {
+					if err := serveStatus(ctx, subOpts.status, feeders, logger); err != nil {
+						logger.Error("status server stopped", "err", err)
 					}
-				}
-			}()
+				}()
+				logger.Info("serving status", "addr", subOpts.status)
+			}
 
-			<-quit
+			// a pair failing stops the others, so that the process exits with its error instead of running without it
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
 
-			return oracle.Delete()
+			var wg sync.WaitGroup
+			var once sync.Once
+			var failed error
+			for _, f := range feeders {
+				wg.Add(1)
+				go func(f *feeder) {
+					defer wg.Done()
+					if err := f.run(ctx); err != nil {
+						f.logger.Error("feed stopped", "err", err)
+						once.Do(func() {
+							failed = util.ChainError(errors.New("failed to feed "+f.name), err)
+							cancel()
+						})
+					}
+				}(f)
+			}
+			wg.Wait()
+
+			return failed
 		},
 	}
 }
+
+func newFeeder(
+	ctx context.Context,
+	opts *Options,
+	pair PairConfig,
+	addresses *chainlog.Addresses,
+	backend transact.Backend,
+	nonces map[common.Address]*transact.NonceManager,
+	logger log.Logger,
+) (*feeder, error) {
+	privKey, err := transact.GetPrivFromFile(pair.Keystore, opts.password)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, err := getContracts(ctx, addresses, backend, pair.Address, pair.Median)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := source.New(pair.Source, pair.JsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	oracle, err := transact.New(ctx, backend, privKey, contracts, opts.osm, opts.median, logger)
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+
+	opts.configure(oracle)
+
+	manager, ok := nonces[oracle.From()]
+	if !ok {
+		manager = transact.NewNonceManager()
+		nonces[oracle.From()] = manager
+	}
+	oracle.SetNonceManager(manager)
+
+	if pair.Wat != "" {
+		if err := oracle.ValidateWat(pair.Wat); err != nil {
+			src.Close()
+			return nil, err
+		}
+	}
+
+	// the spread and the heartbeat compare with the median value, which would fail to be read on every round
+	feedPolicy := policy.Policy{Spread: pair.Spread, Heartbeat: pair.Heartbeat}
+	if feedPolicy.Enabled() {
+		if err := oracle.RequireMedianBud(ctx); err != nil {
+			src.Close()
+			return nil, err
+		}
+	}
+
+	if len(pair.Signers) != 0 {
+		signers, err := transact.GetPrivsFromPaths(pair.Signers, opts.password)
+		if err == nil {
+			err = oracle.SetSigners(ctx, signers...)
+		}
+		if err != nil {
+			src.Close()
+			return nil, err
+		}
+	}
+	for _, signer := range oracle.Signers() {
+		logger.Info("signer", "address", signer)
+	}
+
+	return &feeder{
+		name:     pair.Name,
+		oracle:   oracle,
+		src:      src,
+		policy:   feedPolicy,
+		interval: pair.Interval,
+		blocks:   pair.EveryBlocks,
+		pokeOsm:  *pair.PokeOsm && oracle.HasOsm(),
+		logger:   logger,
+	}, nil
+}
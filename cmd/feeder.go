@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/tgfukuda/test-feed/metrics"
+	"github.com/tgfukuda/test-feed/policy"
+	"github.com/tgfukuda/test-feed/source"
+	"github.com/tgfukuda/test-feed/transact"
+)
+
+const (
+	// osmRetry is the minimum delay between osm checks
+	osmRetry = 5 * time.Second
+	// subscribeRetry is the delay before subscribing again to new heads
+	subscribeRetry = 5 * time.Second
+)
+
+// feeder runs the poke loop of a single pair.
+type feeder struct {
+	name     string
+	oracle   *transact.Oracle
+	src      source.Source
+	policy   policy.Policy
+	interval time.Duration
+	blocks   uint64
+	pokeOsm  bool
+	metrics  *metrics.Pair // nil when metrics are disabled
+	logger   log.Logger
+	state    feedState
+}
+
+// feed pokes the median with the latest price from the source when the policy allows it.
+func (f *feeder) feed(ctx context.Context) {
+	defer f.observe(ctx)
+
+	now := time.Now()
+	price, err := f.src.Price(now)
+	if err != nil {
+		f.logger.Error("failed to read the source", "err", err)
+		return
+	}
+	f.priced(price)
+
+	if f.policy.Enabled() {
+		current, age, err := f.oracle.GetMedianState(ctx)
+		if err != nil {
+			f.logger.Error("failed to read the median", "err", err)
+			return
+		}
+
+		decision := f.policy.Evaluate(current, age, price, now)
+		if !decision.Poke {
+			f.logger.Info("skip poke", "reason", decision.Reason, "price", price)
+			return
+		}
+		f.logger.Debug("poke", "reason", decision.Reason)
+	}
+
+	f.metrics.Attempt(metrics.Median)
+	tx, err := f.oracle.Poke(ctx, func(_ time.Time) (*big.Int, error) {
+		return price, nil
+	})
+	f.record(metrics.Median, tx, err)
+	if err != nil {
+		f.logger.Error("poke failed", "price", price, "err", err)
+		return
+	}
+	f.logger.Info("poked median", "price", price, "hash", tx.Hash())
+	f.metrics.Price(price)
+}
+
+// record keeps the outcome of a poke sent to contract for the status and the metrics.
+// Errors other than reverts are only counted as attempts.
+func (f *feeder) record(contract string, result *transact.TxResult, err error) {
+	f.sent(contract, result, err)
+
+	var gasUsed uint64
+	if result != nil && result.Receipt != nil {
+		gasUsed = result.Receipt.GasUsed
+	}
+
+	var revert *transact.RevertError
+	switch {
+	case err == nil:
+		f.metrics.Success(contract, gasUsed)
+	case errors.As(err, &revert):
+		reason := metrics.UnknownReason
+		if revert.Decoded {
+			reason = revert.Reason
+		}
+		f.metrics.Revert(contract, reason, gasUsed)
+	}
+}
+
+// observe reads the balance and the update times of the contracts for the metrics.
+func (f *feeder) observe(ctx context.Context) {
+	if f.metrics == nil {
+		return
+	}
+
+	balance, err := f.oracle.Balance(ctx)
+	if err != nil {
+		f.logger.Warn("failed to read the balance", "err", err)
+	} else {
+		f.metrics.Balance(f.oracle.From().Hex(), balance)
+	}
+
+	// a zero age means the contract has never been updated
+	age, err := f.oracle.GetMedianAge(ctx)
+	if err != nil {
+		f.logger.Warn("failed to read the median age", "err", err)
+	} else if age.Unix() != 0 {
+		f.metrics.Updated(metrics.Median, age)
+	}
+
+	if f.oracle.HasOsm() {
+		zzz, _, err := f.oracle.GetOsmSchedule(ctx)
+		if err != nil {
+			f.logger.Warn("failed to read the osm schedule", "err", err)
+		} else if zzz.Unix() != 0 {
+			f.metrics.Updated(metrics.Osm, zzz)
+		}
+	}
+}
+
+// advanceOsm pokes osm when a hop has elapsed and returns the delay until the next hop
+func (f *feeder) advanceOsm(ctx context.Context) time.Duration {
+	defer f.observe(ctx)
+
+	pass, err := f.oracle.OsmPass(ctx)
+	if err != nil {
+		f.logger.Error("failed to check the osm", "err", err)
+		return osmRetry
+	}
+	if pass {
+		f.metrics.Attempt(metrics.Osm)
+		tx, err := f.oracle.PokeOsm(ctx)
+		f.record(metrics.Osm, tx, err)
+		if err != nil {
+			f.logger.Error("osm poke failed", "err", err)
+			return osmRetry
+		}
+		f.logger.Info("poked osm", "hash", tx.Hash())
+	}
+
+	zzz, hop, err := f.oracle.GetOsmSchedule(ctx)
+	if err != nil {
+		f.logger.Error("failed to read the osm schedule", "err", err)
+		return osmRetry
+	}
+	next := zzz.Add(hop)
+	f.scheduledOsm(next)
+	f.logger.Info("next osm poke", "at", next.Format(time.RFC3339))
+
+	// chain time may lag behind the local clock, so never spin on an elapsed hop
+	if delay := time.Until(next); osmRetry < delay {
+		return delay
+	}
+	return osmRetry
+}
+
+// schedule records when the next feed is due, a block after lastBlock or an interval from now.
+func (f *feeder) schedule(lastBlock uint64) {
+	if f.blocks == 0 {
+		f.scheduled(time.Now().Add(f.interval), 0)
+	} else if lastBlock != 0 {
+		f.scheduled(time.Time{}, lastBlock+f.blocks)
+	}
+}
+
+// run feeds until ctx is canceled.
+// Either a wall-clock ticker or a newHeads subscription drives the feed.
+func (f *feeder) run(ctx context.Context) error {
+	var (
+		tick      <-chan time.Time
+		heads     chan *types.Header
+		sub       ethereum.Subscription
+		subErr    <-chan error
+		resub     <-chan time.Time
+		lastBlock uint64
+		osmTimer  <-chan time.Time
+		err       error
+	)
+	if f.blocks == 0 {
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	} else {
+		heads = make(chan *types.Header, 16)
+		sub, err = f.oracle.SubscribeHeads(ctx, heads)
+		if err != nil {
+			return err
+		}
+		subErr = sub.Err()
+		defer func() { sub.Unsubscribe() }()
+	}
+
+	f.feed(ctx)
+	f.schedule(lastBlock)
+
+	if f.pokeOsm {
+		osmTimer = time.After(f.advanceOsm(ctx))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			f.logger.Info("closing session")
+			return nil
+		case <-tick:
+			f.feed(ctx)
+			f.schedule(lastBlock)
+		case head := <-heads:
+			number := head.Number.Uint64()
+			switch {
+			case number < lastBlock:
+				// a reorg or a failover to a lagging node, which must not wrap number-lastBlock around
+				f.logger.Debug("head behind the last one", "number", number, "last", lastBlock)
+				lastBlock = number
+				f.schedule(lastBlock)
+			case lastBlock == 0:
+				lastBlock = number
+				f.schedule(lastBlock)
+			case f.blocks <= number-lastBlock:
+				lastBlock = number
+				f.feed(ctx)
+				f.schedule(lastBlock)
+			}
+		case err := <-subErr:
+			f.logger.Warn("new heads subscription failed", "err", err)
+			subErr, resub = nil, time.After(subscribeRetry)
+		case <-resub:
+			next, err := f.oracle.SubscribeHeads(ctx, heads)
+			if err != nil {
+				f.logger.Warn("failed to subscribe to new heads", "err", err)
+				resub = time.After(subscribeRetry)
+				continue
+			}
+			sub, subErr, resub = next, next.Err(), nil
+		case <-osmTimer:
+			osmTimer = time.After(f.advanceOsm(ctx))
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tgfukuda/test-feed/chainlog"
+	"github.com/tgfukuda/test-feed/transact"
+)
+
+var errBlockRange = errors.New("--from is after --to")
+
+func errHistoryFormat(format string) error {
+	return fmt.Errorf("unknown output %q. csv or jsonl", format)
+}
+
+type HistoryOption struct {
+	from     uint64
+	to       uint64
+	output   string
+	reverted bool
+}
+
+// PokeReport is a poke exported by `history`, one json line per poke.
+type PokeReport struct {
+	Block        uint64              `json:"block"`
+	Time         time.Time           `json:"time"`
+	Tx           string              `json:"tx"`
+	From         string              `json:"from"`
+	Status       uint64              `json:"status"`
+	GasUsed      uint64              `json:"gas_used"`
+	Median       string              `json:"median,omitempty"` // absent when the poke reverted
+	Observations []ObservationReport `json:"observations"`
+}
+
+// ObservationReport is a signed price of a poke.
+type ObservationReport struct {
+	Val    string    `json:"val"`
+	Age    time.Time `json:"age"`
+	Signer string    `json:"signer"`
+}
+
+func newHistoryCommand(opts *Options) *cobra.Command {
+	subOpts := HistoryOption{}
+	cmd := historyCommand(opts, &subOpts)
+	cmd.Flags().Uint64Var(
+		&subOpts.from,
+		"from",
+		0,
+		"first block to read",
+	)
+	cmd.Flags().Uint64Var(
+		&subOpts.to,
+		"to",
+		0,
+		"last block to read. the latest block if 0",
+	)
+	cmd.Flags().StringVarP(
+		&subOpts.output,
+		"output",
+		"o",
+		"csv",
+		"csv (a row per observation) or jsonl (a line per poke)",
+	)
+	cmd.Flags().BoolVar(
+		&subOpts.reverted,
+		"reverted",
+		false,
+		"include reverted pokes by reading every block of the range instead of the logs. slow on long ranges",
+	)
+
+	return cmd
+}
+
+func historyCommand(opts *Options, subOpts *HistoryOption) *cobra.Command {
+	return &cobra.Command{
+		Use:   "history",
+		Args:  cobra.ExactArgs(1),
+		Short: "export the pokes sent to the median",
+		Long:  `decodes the vals, ages and signers of each poke mined in a block range and prints them to stdout.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if subOpts.output != "csv" && subOpts.output != "jsonl" {
+				return errHistoryFormat(subOpts.output)
+			}
+
+			addresses, err := chainlog.Load(args[0])
+			if err != nil {
+				return err
+			}
+
+			privKey, err := transact.GetPrivFromFile(opts.keystore, opts.password)
+			if err != nil {
+				return err
+			}
+
+			logger := opts.logger
+
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, os.Interrupt)
+			defer stop()
+
+			backend, err := opts.dial(logger)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				logger.Debug("disconnecting rpc")
+				backend.Close()
+			}()
+
+			contracts, err := getContracts(ctx, addresses, backend, chainlog.OsmPrefix+opts.name, chainlog.MedianPrefix+opts.name)
+			if err != nil {
+				return err
+			}
+
+			oracle, err := transact.New(ctx, backend, privKey, contracts, opts.osm, opts.median, logger)
+			if err != nil {
+				return err
+			}
+
+			to := subOpts.to
+			if to == 0 {
+				if to, err = oracle.BlockNumber(ctx); err != nil {
+					return err
+				}
+			}
+			if to < subOpts.from {
+				return errBlockRange
+			}
+
+			records, err := oracle.PokeHistory(ctx, subOpts.from, to, subOpts.reverted)
+			if err != nil {
+				return err
+			}
+			logger.Info("read pokes", "from", subOpts.from, "to", to, "pokes", len(records))
+
+			if subOpts.output == "jsonl" {
+				return writeJsonLines(cmd.OutOrStdout(), records)
+			}
+
+			return writeCsv(cmd.OutOrStdout(), records)
+		},
+	}
+}
+
+func newPokeReport(record transact.PokeRecord) PokeReport {
+	report := PokeReport{
+		Block:        record.BlockNumber,
+		Time:         record.Time.UTC(),
+		Tx:           record.TxHash.Hex(),
+		From:         record.From.Hex(),
+		Status:       record.Status,
+		GasUsed:      record.GasUsed,
+		Observations: make([]ObservationReport, len(record.Observations)),
+	}
+	if record.Median != nil {
+		report.Median = formatWad(record.Median)
+	}
+	for i, obs := range record.Observations {
+		report.Observations[i] = ObservationReport{
+			Val:    formatWad(obs.Val),
+			Age:    obs.Age.UTC(),
+			Signer: obs.Signer.Hex(),
+		}
+	}
+
+	return report
+}
+
+func writeJsonLines(out io.Writer, records []transact.PokeRecord) error {
+	encoder := json.NewEncoder(out)
+	for _, record := range records {
+		if err := encoder.Encode(newPokeReport(record)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeCsv prints a row per observation, repeating the columns of its poke.
+// A poke whose observations could not be decoded gets a single row with the observation columns empty.
+func writeCsv(out io.Writer, records []transact.PokeRecord) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"block", "time", "tx", "from", "status", "gas_used", "median", "index", "val", "age", "signer"})
+	for _, record := range records {
+		report := newPokeReport(record)
+		poke := []string{
+			strconv.FormatUint(report.Block, 10),
+			report.Time.Format(time.RFC3339),
+			report.Tx,
+			report.From,
+			strconv.FormatUint(report.Status, 10),
+			strconv.FormatUint(report.GasUsed, 10),
+			report.Median,
+		}
+		if len(report.Observations) == 0 {
+			w.Write(append(poke, "", "", "", ""))
+		}
+		for i, obs := range report.Observations {
+			w.Write(append(poke[:len(poke):len(poke)],
+				strconv.Itoa(i),
+				obs.Val,
+				obs.Age.Format(time.RFC3339),
+				obs.Signer,
+			))
+		}
+	}
+	w.Flush()
+
+	return w.Error()
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/tgfukuda/test-feed/transact"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+var errParseBar = errors.New("failed to parse bar")
+
+func newMedianCommand(opts *Options) *cobra.Command {
+	subOpts := AdminOption{}
+	cmd := newAdminCommand("median", "administrate the Median of the token. the sender must be a ward", &subOpts)
+	cmd.AddCommand(
+		adminCommand(opts, &subOpts, "lift <feeder>...", "authorize feeders to sign observations", cobra.MinimumNArgs(1),
+			func(ctx context.Context, oracle *transact.Oracle, args []string) (*transact.TxResult, error) {
+				feeders, err := parseAddresses(args)
+				if err != nil {
+					return nil, err
+				}
+				return oracle.Lift(ctx, feeders...)
+			}),
+		adminCommand(opts, &subOpts, "drop <feeder>...", "revoke feeders", cobra.MinimumNArgs(1),
+			func(ctx context.Context, oracle *transact.Oracle, args []string) (*transact.TxResult, error) {
+				feeders, err := parseAddresses(args)
+				if err != nil {
+					return nil, err
+				}
+				return oracle.Drop(ctx, feeders...)
+			}),
+		adminCommand(opts, &subOpts, "set-bar <bar>", "set the number of observations in each poke. must be odd", cobra.ExactArgs(1),
+			func(ctx context.Context, oracle *transact.Oracle, args []string) (*transact.TxResult, error) {
+				bar, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return nil, util.ChainError(errParseBar, err)
+				}
+				return oracle.SetBar(ctx, bar)
+			}),
+		adminCommand(opts, &subOpts, "kiss <reader>...", "allow readers to read the price", cobra.MinimumNArgs(1),
+			func(ctx context.Context, oracle *transact.Oracle, args []string) (*transact.TxResult, error) {
+				readers, err := parseAddresses(args)
+				if err != nil {
+					return nil, err
+				}
+				return oracle.KissMedian(ctx, readers...)
+			}),
+		adminCommand(opts, &subOpts, "diss <reader>...", "stop readers from reading the price", cobra.MinimumNArgs(1),
+			func(ctx context.Context, oracle *transact.Oracle, args []string) (*transact.TxResult, error) {
+				readers, err := parseAddresses(args)
+				if err != nil {
+					return nil, err
+				}
+				return oracle.DissMedian(ctx, readers...)
+			}),
+	)
+
+	return cmd
+}
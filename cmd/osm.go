@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tgfukuda/test-feed/transact"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+var errParseHop = errors.New("failed to parse hop")
+
+func newOsmCommand(opts *Options) *cobra.Command {
+	subOpts := AdminOption{}
+	cmd := newAdminCommand("osm", "administrate the OSM of the token. the sender must be a ward", &subOpts)
+	cmd.AddCommand(
+		adminCommand(opts, &subOpts, "stop", "stop the OSM from being poked", cobra.NoArgs,
+			func(ctx context.Context, oracle *transact.Oracle, _ []string) (*transact.TxResult, error) {
+				return oracle.StopOsm(ctx)
+			}),
+		adminCommand(opts, &subOpts, "start", "let a stopped OSM be poked again", cobra.NoArgs,
+			func(ctx context.Context, oracle *transact.Oracle, _ []string) (*transact.TxResult, error) {
+				return oracle.StartOsm(ctx)
+			}),
+		adminCommand(opts, &subOpts, "change <src>", "read the price from another source", cobra.ExactArgs(1),
+			func(ctx context.Context, oracle *transact.Oracle, args []string) (*transact.TxResult, error) {
+				src, err := parseAddresses(args)
+				if err != nil {
+					return nil, err
+				}
+				return oracle.ChangeOsm(ctx, src[0])
+			}),
+		adminCommand(opts, &subOpts, "step <hop>", "set the delay between pokes (e.g. 10m)", cobra.ExactArgs(1),
+			func(ctx context.Context, oracle *transact.Oracle, args []string) (*transact.TxResult, error) {
+				hop, err := time.ParseDuration(args[0])
+				if err != nil {
+					return nil, util.ChainError(errParseHop, err)
+				}
+				return oracle.SetHop(ctx, hop)
+			}),
+		adminCommand(opts, &subOpts, "void", "clear the current and next prices and stop the OSM", cobra.NoArgs,
+			func(ctx context.Context, oracle *transact.Oracle, _ []string) (*transact.TxResult, error) {
+				return oracle.VoidOsm(ctx)
+			}),
+		adminCommand(opts, &subOpts, "kiss <reader>...", "allow readers to read the prices", cobra.MinimumNArgs(1),
+			func(ctx context.Context, oracle *transact.Oracle, args []string) (*transact.TxResult, error) {
+				readers, err := parseAddresses(args)
+				if err != nil {
+					return nil, err
+				}
+				return oracle.KissOsm(ctx, readers...)
+			}),
+		adminCommand(opts, &subOpts, "diss <reader>...", "stop readers from reading the prices", cobra.MinimumNArgs(1),
+			func(ctx context.Context, oracle *transact.Oracle, args []string) (*transact.TxResult, error) {
+				readers, err := parseAddresses(args)
+				if err != nil {
+					return nil, err
+				}
+				return oracle.DissOsm(ctx, readers...)
+			}),
+	)
+
+	return cmd
+}
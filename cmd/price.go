@@ -1,16 +1,65 @@
 package cmd
 
 import (
-	"errors"
-	"log"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tgfukuda/test-feed/chainlog"
 	"github.com/tgfukuda/test-feed/transact"
-	"github.com/tgfukuda/test-feed/util"
 )
 
+func errOutputFormat(format string) error {
+	return fmt.Errorf("unknown output %q. table or json", format)
+}
+
+func errPollInterval(interval time.Duration) error {
+	return fmt.Errorf("--poll-interval must be positive, got %s", interval)
+}
+
+var wad = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
 type PriceOption struct {
-	direct bool
+	direct   bool
+	output   string
+	watch    bool
+	interval time.Duration
+}
+
+// PriceReport is the state of an oracle printed by `price`.
+type PriceReport struct {
+	Token  string        `json:"token"`
+	Median *MedianReport `json:"median"`
+	Osm    *OsmReport    `json:"osm,omitempty"`
+}
+
+// MedianReport holds the Median state. Price is read with --direct only.
+type MedianReport struct {
+	Address string     `json:"address"`
+	Price   string     `json:"price,omitempty"`
+	Age     *time.Time `json:"age,omitempty"` // last poke, absent until the first one
+	Bar     uint64     `json:"bar"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// OsmReport holds the OSM values and schedule.
+type OsmReport struct {
+	Address  string     `json:"address"`
+	Current  string     `json:"current,omitempty"`
+	Next     string     `json:"next,omitempty"`
+	Zzz      *time.Time `json:"zzz,omitempty"` // last poke, absent until the first one
+	Hop      uint64     `json:"hop"`           // in seconds
+	NextPoke *time.Time `json:"next_poke,omitempty"`
+	Error    string     `json:"error,omitempty"`
 }
 
 func newPriceCmd(opts *Options) *cobra.Command {
@@ -23,6 +72,26 @@ func newPriceCmd(opts *Options) *cobra.Command {
 		false,
 		"get price from median. need to be authorized.",
 	)
+	cmd.Flags().StringVarP(
+		&subOpts.output,
+		"output",
+		"o",
+		"table",
+		"table or json",
+	)
+	cmd.Flags().BoolVarP(
+		&subOpts.watch,
+		"watch",
+		"w",
+		false,
+		"print each Median and OSM update as it lands until interrupted",
+	)
+	cmd.Flags().DurationVar(
+		&subOpts.interval,
+		"poll-interval",
+		5*time.Second,
+		"delay between log reads with --watch when the endpoint does not support subscriptions",
+	)
 
 	return cmd
 }
@@ -32,10 +101,16 @@ func priceCmd(opts *Options, subOpts *PriceOption) *cobra.Command {
 		Use:   "price",
 		Args:  cobra.ExactArgs(1),
 		Short: "get prices from the contract",
-		Long:  ``,
-		RunE: func(_ *cobra.Command, args []string) (err error) {
-			addressPath := args[0]
-			addresses, err := getAddresses(addressPath)
+		Long:  `prints the Median and OSM state to stdout. logs go to stderr.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if subOpts.output != "table" && subOpts.output != "json" {
+				return errOutputFormat(subOpts.output)
+			}
+			if subOpts.watch && subOpts.interval <= 0 {
+				return errPollInterval(subOpts.interval)
+			}
+
+			addresses, err := chainlog.Load(args[0])
 			if err != nil {
 				return err
 			}
@@ -45,33 +120,271 @@ func priceCmd(opts *Options, subOpts *PriceOption) *cobra.Command {
 				return err
 			}
 
-			osm, ok := addresses[OraclePrefix+opts.name].(string)
-			if !ok {
-				return util.ErrCast
+			logger := opts.logger
+
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, os.Interrupt)
+			defer stop()
+
+			backend, err := opts.dial(logger)
+			if err != nil {
+				return err
 			}
+			defer func() {
+				logger.Debug("disconnecting rpc")
+				backend.Close()
+			}()
 
-			logger := log.Default()
+			contracts, err := getContracts(ctx, addresses, backend, chainlog.OsmPrefix+opts.name, chainlog.MedianPrefix+opts.name)
+			if err != nil {
+				return err
+			}
 
-			oracle, err := transact.New(opts.endpoint, privKey, osm, opts.osm, opts.median, logger)
+			oracle, err := transact.New(ctx, backend, privKey, contracts, opts.osm, opts.median, logger)
 			if err != nil {
 				return err
 			}
 
-			if subOpts.direct {
-				price, err := oracle.GetMedianPrice()
-				if err != nil {
-					return util.ChainError(errors.New("failed to get median price"), err)
-				}
-				logger.Printf("price: %d", price)
-			} else {
-				curr, next, err := oracle.GetOsmPrice()
-				if err != nil {
-					return util.ChainError(errors.New("failed to get osm price"), err)
-				}
-				logger.Printf("current: %d, next: %d", curr, next)
+			if subOpts.watch {
+				return watchPrices(ctx, cmd.OutOrStdout(), oracle, opts.name, subOpts)
+			}
+
+			report := readPrices(ctx, oracle, opts.name, subOpts.direct)
+			if subOpts.output == "json" {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
 			}
 
-			return oracle.Delete()
+			return report.writeTable(cmd.OutOrStdout())
 		},
 	}
 }
+
+// readPrices collects the state of the oracle. Failed reads are reported in the Error fields.
+func readPrices(ctx context.Context, oracle *transact.Oracle, token string, direct bool) PriceReport {
+	contracts := oracle.Contracts()
+	report := PriceReport{
+		Token:  token,
+		Median: &MedianReport{Address: contracts.Median.Hex()},
+	}
+
+	var errs []string
+	bar, err := oracle.GetBar(ctx)
+	if err == nil {
+		report.Median.Bar = bar.Uint64()
+	} else {
+		errs = append(errs, err.Error())
+	}
+	age, err := oracle.GetMedianAge(ctx)
+	if err == nil {
+		report.Median.Age = unixOrNil(age)
+	} else {
+		errs = append(errs, err.Error())
+	}
+	if direct {
+		price, err := oracle.GetMedianPrice(ctx)
+		if err == nil {
+			report.Median.Price = formatWad(price)
+		} else {
+			errs = append(errs, err.Error())
+		}
+	}
+	report.Median.Error = strings.Join(errs, "; ")
+
+	if !oracle.HasOsm() {
+		return report
+	}
+
+	report.Osm = &OsmReport{Address: contracts.Osm.Hex()}
+	errs = nil
+	zzz, hop, err := oracle.GetOsmSchedule(ctx)
+	if err == nil {
+		report.Osm.Zzz = unixOrNil(zzz)
+		report.Osm.Hop = uint64(hop / time.Second)
+		next := zzz.Add(hop)
+		report.Osm.NextPoke = &next
+	} else {
+		errs = append(errs, err.Error())
+	}
+	if !direct {
+		current, next, err := oracle.GetOsmPrice(ctx)
+		if err == nil {
+			report.Osm.Current, report.Osm.Next = formatWad(current), formatWad(next)
+		} else {
+			errs = append(errs, err.Error())
+		}
+	}
+	report.Osm.Error = strings.Join(errs, "; ")
+
+	return report
+}
+
+// watchPrices prints the updates logged after the latest block, with the change from the previous value.
+func watchPrices(ctx context.Context, out io.Writer, oracle *transact.Oracle, token string, subOpts *PriceOption) error {
+	head, err := oracle.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	previous := currentPrices(ctx, oracle, subOpts.direct)
+
+	updates := make(chan transact.PriceUpdate)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- oracle.WatchPrices(ctx, head+1, subOpts.interval, updates)
+	}()
+
+	encoder := json.NewEncoder(out)
+	for {
+		select {
+		case err := <-errc:
+			return err
+		case update := <-updates:
+			change := newPriceChange(update, previous[update.Contract])
+			previous[update.Contract] = update.Price
+			if subOpts.output == "json" {
+				err = encoder.Encode(change)
+			} else {
+				err = change.writeLine(out, token)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// currentPrices reads the values the first updates are compared to.
+// The Median price needs --direct and the OSM one needs the opposite, so one of them is usually unknown.
+func currentPrices(ctx context.Context, oracle *transact.Oracle, direct bool) map[string]*big.Int {
+	prices := make(map[string]*big.Int)
+	if direct {
+		if price, err := oracle.GetMedianPrice(ctx); err == nil {
+			prices[transact.MedianContract] = price
+		}
+	} else if oracle.HasOsm() {
+		if current, _, err := oracle.GetOsmPrice(ctx); err == nil {
+			prices[transact.OsmContract] = current
+		}
+	}
+
+	return prices
+}
+
+// PriceChange is a price update printed by `price --watch`.
+type PriceChange struct {
+	Contract string `json:"contract"`
+	Block    uint64 `json:"block"`
+	Tx       string `json:"tx"`
+	Previous string `json:"previous,omitempty"` // absent when the previous value could not be read
+	Price    string `json:"price"`
+	Change   string `json:"change,omitempty"`
+	Percent  string `json:"percent,omitempty"`
+}
+
+func newPriceChange(update transact.PriceUpdate, previous *big.Int) PriceChange {
+	change := PriceChange{
+		Contract: update.Contract,
+		Block:    update.BlockNumber,
+		Tx:       update.TxHash.Hex(),
+		Price:    formatWad(update.Price),
+	}
+	if previous == nil {
+		return change
+	}
+
+	delta := new(big.Int).Sub(update.Price, previous)
+	change.Previous = formatWad(previous)
+	change.Change = formatWad(delta)
+	if 0 <= delta.Sign() {
+		change.Change = "+" + change.Change
+	}
+	if previous.Sign() != 0 {
+		percent := new(big.Float).Quo(new(big.Float).SetInt(delta), new(big.Float).SetInt(previous))
+		change.Percent = percent.Mul(percent, big.NewFloat(100)).Text('f', 2)
+		if 0 <= delta.Sign() {
+			change.Percent = "+" + change.Percent
+		}
+	}
+
+	return change
+}
+
+// writeLine prints the change as `block <n>  <tx>  <contract>  <previous> → <price> (<change>, <percent>%)`.
+func (change PriceChange) writeLine(out io.Writer, token string) error {
+	label := chainlog.MedianPrefix + token
+	if change.Contract == transact.OsmContract {
+		label = chainlog.OsmPrefix + token
+	}
+	previous, delta := "?", ""
+	if change.Previous != "" {
+		previous, delta = change.Previous, " ("+change.Change
+		if change.Percent != "" {
+			delta += ", " + change.Percent + "%"
+		}
+		delta += ")"
+	}
+
+	_, err := fmt.Fprintf(out, "block %d  %s  %s  %s → %s%s\n", change.Block, change.Tx, label, previous, change.Price, delta)
+	return err
+}
+
+// writeTable prints the report as aligned name/value rows.
+func (report PriceReport) writeTable(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	row := func(name string, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%s\t%s\n", name, value)
+		}
+	}
+
+	row(chainlog.MedianPrefix+report.Token, report.Median.Address)
+	row("price", report.Median.Price)
+	row("age", formatTime(report.Median.Age))
+	row("bar", fmt.Sprint(report.Median.Bar))
+	row("error", report.Median.Error)
+	if report.Osm != nil {
+		row(chainlog.OsmPrefix+report.Token, report.Osm.Address)
+		row("current", report.Osm.Current)
+		row("next", report.Osm.Next)
+		row("zzz", formatTime(report.Osm.Zzz))
+		row("hop", (time.Duration(report.Osm.Hop) * time.Second).String())
+		row("next poke", formatTime(report.Osm.NextPoke))
+		row("error", report.Osm.Error)
+	}
+
+	return w.Flush()
+}
+
+// formatWad renders a WAD scaled integer as a decimal without trailing zeros.
+func formatWad(value *big.Int) string {
+	integer, fraction := new(big.Int).QuoRem(value, wad, new(big.Int))
+	if fraction.Sign() == 0 {
+		return integer.String()
+	}
+	digits := strings.TrimRight(fmt.Sprintf("%018s", new(big.Int).Abs(fraction).String()), "0")
+	if value.Sign() < 0 && integer.Sign() == 0 {
+		return "-0." + digits
+	}
+
+	return integer.String() + "." + digits
+}
+
+// formatTime renders t with the time left until it or elapsed since it.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	if until := time.Until(*t).Truncate(time.Second); 0 < until {
+		return fmt.Sprintf("%s (in %s)", t.Local().Format(time.RFC3339), until)
+	}
+
+	return fmt.Sprintf("%s (%s ago)", t.Local().Format(time.RFC3339), time.Since(*t).Truncate(time.Second))
+}
+
+// unixOrNil drops the zero timestamp of a contract never poked.
+func unixOrNil(t time.Time) *time.Time {
+	if t.Unix() == 0 {
+		return nil
+	}
+	return &t
+}
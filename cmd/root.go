@@ -5,47 +5,153 @@ Copyright © 2022 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"io/ioutil"
+	"fmt"
+	"math/big"
 	"os"
+	"time"
 
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/spf13/cobra"
+	"github.com/tgfukuda/test-feed/chainlog"
+	"github.com/tgfukuda/test-feed/transact"
 	"github.com/tgfukuda/test-feed/util"
 )
 
 type Options struct {
-	endpoint string
-	name     string
-	keystore string
-	password string
-	osm      string
-	median   string
+	endpoint  []string
+	name      string
+	keystore  string
+	password  string
+	osm       string
+	median    string
+	tipCap    float64
+	feeCap    float64
+	gasMul    float64
+	gasCeil   uint64
+	replace   time.Duration
+	feeBump   uint64
+	confirms  uint64
+	timeout   time.Duration
+	trace     bool
+	probe     time.Duration
+	maxAge    time.Duration
+	logLevel  string
+	logFormat string
+	logger    log.Logger // built from logLevel and logFormat before any command runs
 }
 
-var errGetAddresses = errors.New("failed to get addresses")
+func errLogFormat(format string) error {
+	return fmt.Errorf("unknown log format %q. text, logfmt or json", format)
+}
 
-func getAddresses(addressPath string) (map[string]interface{}, error) {
-	addressFile, err := os.Open(addressPath)
+// newLogger writes records at logLevel or above to stderr in logFormat.
+func (opts *Options) newLogger() (log.Logger, error) {
+	level, err := log.LvlFromString(opts.logLevel)
 	if err != nil {
-		return nil, util.ChainError(errGetAddresses, err)
+		return nil, err
 	}
-	defer addressFile.Close()
 
-	bytes, err := ioutil.ReadAll(addressFile)
-	if err != nil {
-		return nil, util.ChainError(errGetAddresses, err)
+	var format log.Format
+	switch opts.logFormat {
+	case "text":
+		format = log.TerminalFormat(false)
+	case "logfmt":
+		format = log.LogfmtFormat()
+	case "json":
+		format = log.JSONFormat()
+	default:
+		return nil, errLogFormat(opts.logFormat)
+	}
+
+	logger := log.New()
+	logger.SetHandler(log.LvlFilterHandler(level, log.StreamHandler(os.Stderr, format)))
+
+	return logger, nil
+}
+
+// dial connects to the configured endpoints, failing over between them
+func (opts *Options) dial(logger log.Logger) (*transact.Pool, error) {
+	return transact.Dial(opts.endpoint, transact.PoolConfig{
+		ProbeInterval: opts.probe,
+		MaxBlockAge:   opts.maxAge,
+	}, logger)
+}
+
+// gwei converts a gwei amount into wei. zero is treated as unset.
+func gwei(amount float64) *big.Int {
+	if amount <= 0 {
+		return nil
+	}
+	wei, _ := new(big.Float).Mul(big.NewFloat(amount), big.NewFloat(params.GWei)).Int(nil)
+	return wei
+}
+
+func (opts *Options) gasConfig() transact.GasConfig {
+	return transact.GasConfig{
+		Multiplier: opts.gasMul,
+		Ceiling:    opts.gasCeil,
 	}
+}
+
+func (opts *Options) nonceConfig() transact.NonceConfig {
+	config := transact.DefaultNonceConfig
+	config.ReplaceTimeout = opts.replace
+	config.FeeBump = opts.feeBump
+	return config
+}
 
-	var addresses map[string]interface{}
-	if err := json.Unmarshal([]byte(bytes), &addresses); err != nil {
-		return nil, util.ChainError(errGetAddresses, err)
+func (opts *Options) confirmConfig() transact.ConfirmConfig {
+	return transact.ConfirmConfig{
+		Confirmations: opts.confirms,
+		Timeout:       opts.timeout,
 	}
+}
 
-	return addresses, nil
+// configure applies the transaction settings from the flags to oracle
+func (opts *Options) configure(oracle *transact.Oracle) {
+	oracle.SetFeeConfig(opts.feeConfig())
+	oracle.SetGasConfig(opts.gasConfig())
+	oracle.SetNonceConfig(opts.nonceConfig())
+	oracle.SetConfirmConfig(opts.confirmConfig())
+	oracle.SetTrace(opts.trace)
 }
 
-const OraclePrefix = "PIP_"
+func (opts *Options) feeConfig() transact.FeeConfig {
+	return transact.FeeConfig{
+		TipCap: gwei(opts.tipCap),
+		FeeCap: gwei(opts.feeCap),
+	}
+}
+
+var errCheckAddresses = errors.New("failed to check addresses")
+
+// getContracts looks up the OSM and Median by key and checks that they hold code.
+// Either may be missing, but not both.
+func getContracts(ctx context.Context, addresses *chainlog.Addresses, backend transact.Backend, osmKey string, medianKey string) (transact.Contracts, error) {
+	var contracts transact.Contracts
+	keys := []string{}
+	if addresses.Has(medianKey) {
+		contracts.Median, _ = addresses.Get(medianKey)
+		keys = append(keys, medianKey)
+	}
+	if addresses.Has(osmKey) || !addresses.Has(medianKey) {
+		osm, err := addresses.Get(osmKey)
+		if err != nil {
+			return transact.Contracts{}, err
+		}
+		contracts.Osm = osm
+		keys = append(keys, osmKey)
+	}
+
+	if err := addresses.Verify(ctx, backend, keys...); err != nil {
+		return transact.Contracts{}, util.ChainError(errCheckAddresses, err)
+	}
+
+	return contracts, nil
+}
 
 func NewRootCommand(opts *Options) *cobra.Command {
 	rootCmd := &cobra.Command{
@@ -54,6 +160,10 @@ func NewRootCommand(opts *Options) *cobra.Command {
 		Long:          "this client is for testing. DO NOT USE in production.",
 		SilenceErrors: false,
 		SilenceUsage:  true,
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) (err error) {
+			opts.logger, err = opts.newLogger()
+			return err
+		},
 	}
 
 	rootCmd.PersistentFlags().StringVarP(
@@ -63,11 +173,23 @@ func NewRootCommand(opts *Options) *cobra.Command {
 		"ETH",
 		"target token name",
 	)
-	rootCmd.PersistentFlags().StringVar(
+	rootCmd.PersistentFlags().StringSliceVar(
 		&opts.endpoint,
 		"endpoint",
-		"http://127.0.0.1:8545",
-		"rpc server",
+		[]string{"http://127.0.0.1:8545"},
+		"rpc servers (http, ws or ipc). later ones are used for failover",
+	)
+	rootCmd.PersistentFlags().DurationVar(
+		&opts.probe,
+		"probe-interval",
+		transact.DefaultPoolConfig.ProbeInterval,
+		"delay between health checks of the rpc servers. 0 checks only when every server has failed",
+	)
+	rootCmd.PersistentFlags().DurationVar(
+		&opts.maxAge,
+		"max-block-age",
+		transact.DefaultPoolConfig.MaxBlockAge,
+		"treat an rpc server whose latest block is older as unhealthy. 0 disables",
 	)
 	rootCmd.PersistentFlags().StringVarP(
 		&opts.keystore,
@@ -88,19 +210,90 @@ func NewRootCommand(opts *Options) *cobra.Command {
 	rootCmd.PersistentFlags().StringVar(
 		&opts.median,
 		"median",
-		"./median/out/MedianJPXJPY.abi",
-		"Median ABI file",
+		"",
+		"Median ABI file overriding the embedded one",
 	)
 	rootCmd.PersistentFlags().StringVar(
 		&opts.osm,
 		"osm",
-		"./osm/out/OSM.abi",
-		"OSM ABI file",
+		"",
+		"OSM ABI file overriding the embedded one",
+	)
+
+	rootCmd.PersistentFlags().Float64Var(
+		&opts.tipCap,
+		"tip-cap",
+		0,
+		"max priority fee per gas in gwei. suggested by the node if 0",
+	)
+	rootCmd.PersistentFlags().Float64Var(
+		&opts.feeCap,
+		"fee-cap",
+		0,
+		"max fee per gas in gwei. 2 * base fee + tip cap if 0",
+	)
+	rootCmd.PersistentFlags().Float64Var(
+		&opts.gasMul,
+		"gas-multiplier",
+		transact.DefaultGasConfig.Multiplier,
+		"multiplier applied to the estimated gas",
+	)
+	rootCmd.PersistentFlags().Uint64Var(
+		&opts.gasCeil,
+		"gas-ceiling",
+		transact.DefaultGasConfig.Ceiling,
+		"upper bound of the gas limit. the block gas limit if 0",
+	)
+	rootCmd.PersistentFlags().DurationVar(
+		&opts.replace,
+		"replace-timeout",
+		transact.DefaultNonceConfig.ReplaceTimeout,
+		"re-send a pending transaction with bumped fees after this. 0 disables",
+	)
+	rootCmd.PersistentFlags().Uint64Var(
+		&opts.feeBump,
+		"fee-bump",
+		transact.DefaultNonceConfig.FeeBump,
+		"fee increase in percent for replacement transactions",
+	)
+	rootCmd.PersistentFlags().Uint64Var(
+		&opts.confirms,
+		"confirmations",
+		transact.DefaultConfirmConfig.Confirmations,
+		"blocks to wait on top of the inclusion block",
+	)
+	rootCmd.PersistentFlags().DurationVar(
+		&opts.timeout,
+		"tx-timeout",
+		transact.DefaultConfirmConfig.Timeout,
+		"give up waiting for a transaction after this. 0 waits forever",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&opts.logLevel,
+		"log-level",
+		"info",
+		"minimum level logged. trace, debug, info, warn, error or crit",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&opts.logFormat,
+		"log-format",
+		"text",
+		"log output. text, logfmt or json",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&opts.trace,
+		"trace",
+		false,
+		"read revert reasons with debug_traceTransaction. needs the debug namespace",
 	)
 
 	rootCmd.AddCommand(
 		newFeedCommand(opts),
+		newDeployCommand(opts),
+		newMedianCommand(opts),
+		newOsmCommand(opts),
 		newPriceCmd(opts),
+		newHistoryCommand(opts),
 		newSignCommand(opts),
 		newRecoverCommand(opts),
 		newExportPrivCommand(opts),
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/tgfukuda/test-feed/transact"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+// statusTimeout bounds the chain reads of a single status request
+const statusTimeout = 10 * time.Second
+
+var errServeStatus = errors.New("failed to serve status")
+
+// the status of a poke transaction
+const (
+	txMined    = "mined"
+	txReverted = "reverted"
+	txFailed   = "failed"
+)
+
+// feedState is what a feeder has done and plans to do, kept for the status API.
+type feedState struct {
+	mu          sync.Mutex
+	price       *big.Int
+	pricedAt    time.Time
+	lastTx      *TxStatus
+	nextPoke    time.Time
+	nextBlock   uint64
+	nextOsmPoke time.Time
+}
+
+// PairStatus is the json served for each pair.
+type PairStatus struct {
+	Name        string         `json:"name"`
+	Price       string         `json:"price,omitempty"` // last price read from the source, in wad
+	PricedAt    *time.Time     `json:"priced_at,omitempty"`
+	LastTx      *TxStatus      `json:"last_tx,omitempty"`
+	NextPoke    *time.Time     `json:"next_poke,omitempty"`
+	NextBlock   uint64         `json:"next_block,omitempty"` // with --every-blocks
+	NextOsmPoke *time.Time     `json:"next_osm_poke,omitempty"`
+	Osm         *OsmStatus     `json:"osm,omitempty"`
+	Account     *AccountStatus `json:"account"`
+}
+
+// TxStatus describes the last poke sent by the pair.
+type TxStatus struct {
+	Contract string    `json:"contract"`
+	Hash     string    `json:"hash,omitempty"` // empty when nothing was sent
+	Status   string    `json:"status"`
+	Block    string    `json:"block,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// OsmStatus holds the values returned by GetOsmPrice.
+type OsmStatus struct {
+	Current string `json:"current,omitempty"`
+	Next    string `json:"next,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// AccountStatus describes the sender of the pair.
+type AccountStatus struct {
+	Address string `json:"address"`
+	Balance string `json:"balance,omitempty"` // in wei
+	Nonce   uint64 `json:"nonce"`
+	Error   string `json:"error,omitempty"`
+}
+
+// priced records the price read from the source.
+func (f *feeder) priced(price *big.Int) {
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+
+	f.state.price, f.state.pricedAt = price, time.Now()
+}
+
+// sent records the outcome of a poke sent to contract.
+func (f *feeder) sent(contract string, result *transact.TxResult, err error) {
+	status := &TxStatus{Contract: contract, Status: txMined, At: time.Now()}
+	if result != nil {
+		status.Hash = result.Hash().Hex()
+		if result.BlockNumber != nil {
+			status.Block = result.BlockNumber.String()
+		}
+	}
+	var revert *transact.RevertError
+	switch {
+	case errors.As(err, &revert):
+		status.Status, status.Error = txReverted, revert.Reason
+	case err != nil:
+		status.Status, status.Error = txFailed, err.Error()
+	}
+
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+	f.state.lastTx = status
+}
+
+// scheduled records the next poke of the median, by time or by block.
+func (f *feeder) scheduled(next time.Time, block uint64) {
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+
+	f.state.nextPoke, f.state.nextBlock = next, block
+}
+
+// scheduledOsm records the next osm poke.
+func (f *feeder) scheduledOsm(next time.Time) {
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+
+	f.state.nextOsmPoke = next
+}
+
+// status combines the state of the feeder with the osm values and the account read from chain.
+func (f *feeder) status(ctx context.Context) PairStatus {
+	f.state.mu.Lock()
+	status := PairStatus{
+		Name:        f.name,
+		LastTx:      f.state.lastTx,
+		NextBlock:   f.state.nextBlock,
+		PricedAt:    timeOrNil(f.state.pricedAt),
+		NextPoke:    timeOrNil(f.state.nextPoke),
+		NextOsmPoke: timeOrNil(f.state.nextOsmPoke),
+	}
+	if f.state.price != nil {
+		status.Price = f.state.price.String()
+	}
+	f.state.mu.Unlock()
+
+	if f.oracle.HasOsm() {
+		status.Osm = &OsmStatus{}
+		if current, next, err := f.oracle.GetOsmPrice(ctx); err != nil {
+			status.Osm.Error = err.Error()
+		} else {
+			status.Osm.Current, status.Osm.Next = current.String(), next.String()
+		}
+	}
+
+	status.Account = &AccountStatus{Address: f.oracle.From().Hex()}
+	balance, err := f.oracle.Balance(ctx)
+	if err == nil {
+		status.Account.Balance = balance.String()
+		status.Account.Nonce, err = f.oracle.Nonce(ctx)
+	}
+	if err != nil {
+		status.Account.Error = err.Error()
+	}
+
+	return status
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// serveStatus exposes /pairs and /pairs/<name> on addr until ctx is canceled.
+func serveStatus(ctx context.Context, addr string, feeders []*feeder, logger log.Logger) error {
+	byName := make(map[string]*feeder, len(feeders))
+	for _, f := range feeders {
+		byName[f.name] = f
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pairs", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), statusTimeout)
+		defer cancel()
+
+		statuses := make([]PairStatus, len(feeders))
+		for i, f := range feeders {
+			statuses[i] = f.status(ctx)
+		}
+		writeJson(w, http.StatusOK, statuses, logger)
+	})
+	mux.HandleFunc("/pairs/", func(w http.ResponseWriter, r *http.Request) {
+		f, ok := byName[strings.TrimPrefix(r.URL.Path, "/pairs/")]
+		if !ok {
+			writeJson(w, http.StatusNotFound, map[string]string{"error": "unknown pair"}, logger)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), statusTimeout)
+		defer cancel()
+		writeJson(w, http.StatusOK, f.status(ctx), logger)
+	})
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return util.ChainError(errServeStatus, err)
+	}
+
+	return nil
+}
+
+func writeJson(w http.ResponseWriter, code int, body interface{}, logger log.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logger.Warn("failed to write status", "err", err)
+	}
+}
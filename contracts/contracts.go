@@ -0,0 +1,33 @@
+// Package contracts embeds the standard Median and OSM interfaces and their typed bindings,
+// along with the solc output of makerdao/median and makerdao/osm when it is generated from <name>.bin.
+package contracts
+
+//go:generate go run gen.go
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BindMedian binds a Median with parsed, which may override the embedded abi.
+// The bound contract is returned as well for methods missing from the binding.
+func BindMedian(address common.Address, parsed abi.ABI, backend bind.ContractBackend) (*Median, *bind.BoundContract) {
+	contract := bind.NewBoundContract(address, parsed, backend, backend, backend)
+	return &Median{
+		MedianCaller:     MedianCaller{contract: contract},
+		MedianTransactor: MedianTransactor{contract: contract},
+		MedianFilterer:   MedianFilterer{contract: contract},
+	}, contract
+}
+
+// BindOSM binds an OSM with parsed, which may override the embedded abi.
+// The bound contract is returned as well for methods missing from the binding.
+func BindOSM(address common.Address, parsed abi.ABI, backend bind.ContractBackend) (*OSM, *bind.BoundContract) {
+	contract := bind.NewBoundContract(address, parsed, backend, backend, backend)
+	return &OSM{
+		OSMCaller:     OSMCaller{contract: contract},
+		OSMTransactor: OSMTransactor{contract: contract},
+		OSMFilterer:   OSMFilterer{contract: contract},
+	}, contract
+}
@@ -0,0 +1,50 @@
+//go:build ignore
+
+// gen writes the Go bindings of the contracts in this directory, the same as
+// `abigen --abi <name>.abi --bin <name>.bin --type <Type> --pkg contracts --out <name>.go`.
+// <name>.bin is the solc output of makerdao/median and makerdao/osm built by deploy-oracle.sh with EMBED_BIN=true.
+// Without it the binding is generated from the abi alone and cannot deploy.
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+var bindings = []struct {
+	name string
+	kind string
+}{
+	{"median", "Median"},
+	{"osm", "OSM"},
+}
+
+func main() {
+	for _, binding := range bindings {
+		abi, err := os.ReadFile(binding.name + ".abi")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		bin, err := os.ReadFile(binding.name + ".bin")
+		if errors.Is(err, fs.ErrNotExist) {
+			log.Printf("no %s.bin, %s is generated without bytecode", binding.name, binding.kind)
+		} else if err != nil {
+			log.Fatal(err)
+		}
+
+		code, err := bind.Bind([]string{binding.kind}, []string{string(abi)}, []string{strings.TrimSpace(string(bin))}, nil, "contracts", bind.LangGo, nil, nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := os.WriteFile(binding.name+".go", []byte(code), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -9,7 +9,10 @@ FROM=${3:-JPYF}
 TO=${4:-JPY}
 TOKENPAIR=$FROM$TO
 
-EXTRACT_ABI=true
+## the feed embeds the standard abis. extract them only to pass --median/--osm overrides
+EXTRACT_ABI=${EXTRACT_ABI:-false}
+## copy the built Median$TOKENPAIR and OSM bytecode into contracts/ so that `test-feed deploy` and the tests can deploy them
+EMBED_BIN=${EMBED_BIN:-false}
 
 RPC=localhost
 PORT=8545
@@ -21,6 +24,10 @@ export ETH_KEYSTORE=$BASEDIR/testnet/$PORT/keystore
 export ETH_PASSWORD=/dev/null
 export ETH_GAS=7000000
 
+## comma separated feeder addresses and the quorum. e.g. FEEDERS=0x..,0x..,0x.. BAR=3
+FEEDERS=${FEEDERS:-$ETH_FROM}
+BAR=${BAR:-1}
+
 EXPORT_DIR=$(cd $(dirname ${BASH_SOURCE:-$0}) && pwd)
 
 ## deploy oracle
@@ -45,8 +52,8 @@ OSM=$(dapp create OSM $MEDIAN | tail -n 1)
 [[ $? != 0 ]] && exit 1
 
 ### feed settings
-seth send $MEDIAN 'lift(address[])' "[$ETH_FROM]" &&
-seth send $MEDIAN "setBar(uint256)" $(seth --to-uint256 1) &&
+seth send $MEDIAN 'lift(address[])' "[$FEEDERS]" &&
+seth send $MEDIAN "setBar(uint256)" $(seth --to-uint256 $BAR) &&
 ### give osm median access
 seth send $MEDIAN "kiss(address)" "$OSM"
 ### for debug
@@ -58,6 +65,12 @@ if $EXTRACT_ABI; then
     cd $OSM_DIR && dapp --use solc:0.5.12 build --extract && [[ ! -f out/OSM.abi ]] && echo "[INFO] failed to extract OSM abi"
 fi
 
+if $EMBED_BIN; then
+    cd $MEDIAN_DIR && dapp --use solc:0.5.12 build --extract && cp out/Median$TOKENPAIR.bin $EXPORT_DIR/contracts/median.bin
+    cd $OSM_DIR && dapp --use solc:0.5.12 build --extract && cp out/OSM.bin $EXPORT_DIR/contracts/osm.bin
+    cd $EXPORT_DIR/contracts && go generate .
+fi
+
 echo ""
 echo "MEDIAN=$MEDIAN"
 echo "OSM=$OSM"
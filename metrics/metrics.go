@@ -0,0 +1,233 @@
+// Package metrics exposes the state of the feed loops to Prometheus, labelled per pair.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+const namespace = "testfeed"
+
+// the contract label
+const (
+	Median = "median"
+	Osm    = "osm"
+)
+
+// UnknownReason is the reason label of the reverts whose reason was not decoded from the contract,
+// so that rpc error text and raw revert data never become label values.
+const UnknownReason = "unknown"
+
+// errors
+var errServe = errors.New("failed to serve metrics")
+
+var wad = new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+
+// Metrics holds the collectors of every pair fed by the process.
+type Metrics struct {
+	registry  *prometheus.Registry
+	pokes     *prometheus.CounterVec
+	successes *prometheus.CounterVec
+	reverts   *prometheus.CounterVec
+	gasUsed   *prometheus.CounterVec
+	balance   *prometheus.GaugeVec
+	price     *prometheus.GaugeVec
+	updated   *prometheus.GaugeVec
+	staleness *prometheus.Desc
+
+	mu    sync.Mutex
+	pairs map[string]*Pair
+}
+
+func New() *Metrics {
+	labels := []string{"pair", "contract"}
+	metrics := &Metrics{
+		registry: prometheus.NewRegistry(),
+		pokes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pokes_total",
+			Help:      "Poke transactions attempted.",
+		}, labels),
+		successes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "poke_successes_total",
+			Help:      "Poke transactions mined successfully.",
+		}, labels),
+		reverts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "poke_reverts_total",
+			Help:      "Poke transactions reverted or predicted to revert, by reason.",
+		}, append(labels, "reason")),
+		gasUsed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "gas_used_total",
+			Help:      "Gas used by mined poke transactions.",
+		}, labels),
+		balance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "balance_ether",
+			Help:      "Balance of the sender account.",
+		}, []string{"pair", "account"}),
+		price: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_price",
+			Help:      "Last price pushed to the Median.",
+		}, []string{"pair"}),
+		updated: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "updated_timestamp_seconds",
+			Help:      "Time of the last Median update or OSM poke read from chain.",
+		}, labels),
+		staleness: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "staleness_seconds"),
+			"Seconds since the last Median update or OSM poke.",
+			labels, nil,
+		),
+		pairs: make(map[string]*Pair),
+	}
+
+	metrics.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		metrics.pokes,
+		metrics.successes,
+		metrics.reverts,
+		metrics.gasUsed,
+		metrics.balance,
+		metrics.price,
+		metrics.updated,
+		metrics,
+	)
+
+	return metrics
+}
+
+// Describe implements prometheus.Collector for the staleness, which is computed at scrape time.
+func (metrics *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- metrics.staleness
+}
+
+func (metrics *Metrics) Collect(ch chan<- prometheus.Metric) {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	now := time.Now()
+	for name, pair := range metrics.pairs {
+		pair.mu.Lock()
+		for contract, updated := range pair.updated {
+			ch <- prometheus.MustNewConstMetric(metrics.staleness, prometheus.GaugeValue, now.Sub(updated).Seconds(), name, contract)
+		}
+		pair.mu.Unlock()
+	}
+}
+
+// Pair returns the recorder for the pair called name.
+func (metrics *Metrics) Pair(name string) *Pair {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	pair, ok := metrics.pairs[name]
+	if !ok {
+		pair = &Pair{metrics: metrics, name: name, updated: make(map[string]time.Time)}
+		metrics.pairs[name] = pair
+	}
+
+	return pair
+}
+
+// Serve exposes /metrics on addr until ctx is canceled.
+func (metrics *Metrics) Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(metrics.registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return util.ChainError(errServe, err)
+	}
+
+	return nil
+}
+
+// Pair records the metrics of a single pair.
+// A nil Pair records nothing, so the feed loops need no check when metrics are disabled.
+type Pair struct {
+	metrics *Metrics
+	name    string
+
+	mu      sync.Mutex
+	updated map[string]time.Time
+}
+
+// Attempt counts a poke about to be sent to contract.
+func (pair *Pair) Attempt(contract string) {
+	if pair == nil {
+		return
+	}
+	pair.metrics.pokes.WithLabelValues(pair.name, contract).Inc()
+}
+
+// Success counts a poke mined successfully.
+func (pair *Pair) Success(contract string, gasUsed uint64) {
+	if pair == nil {
+		return
+	}
+	pair.metrics.successes.WithLabelValues(pair.name, contract).Inc()
+	pair.metrics.gasUsed.WithLabelValues(pair.name, contract).Add(float64(gasUsed))
+}
+
+// Revert counts a poke reverted for reason, which must come from a bounded set (see UnknownReason).
+// gasUsed is zero when the revert was predicted and nothing was sent.
+func (pair *Pair) Revert(contract string, reason string, gasUsed uint64) {
+	if pair == nil {
+		return
+	}
+	pair.metrics.reverts.WithLabelValues(pair.name, contract, reason).Inc()
+	pair.metrics.gasUsed.WithLabelValues(pair.name, contract).Add(float64(gasUsed))
+}
+
+// Price records the last price pushed, scaled down from WAD.
+func (pair *Pair) Price(price *big.Int) {
+	if pair == nil {
+		return
+	}
+	pair.metrics.price.WithLabelValues(pair.name).Set(fromWad(price))
+}
+
+// Balance records the balance of account, given in wei.
+func (pair *Pair) Balance(account string, balance *big.Int) {
+	if pair == nil {
+		return
+	}
+	pair.metrics.balance.WithLabelValues(pair.name, account).Set(fromWad(balance))
+}
+
+// Updated records when contract last changed its value on chain.
+func (pair *Pair) Updated(contract string, at time.Time) {
+	if pair == nil {
+		return
+	}
+	pair.metrics.updated.WithLabelValues(pair.name, contract).Set(float64(at.Unix()))
+
+	pair.mu.Lock()
+	defer pair.mu.Unlock()
+	pair.updated[contract] = at
+}
+
+func fromWad(value *big.Int) float64 {
+	scaled, _ := new(big.Float).Quo(new(big.Float).SetInt(value), wad).Float64()
+	return scaled
+}
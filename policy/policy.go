@@ -0,0 +1,67 @@
+package policy
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Policy decides whether a new price is worth a poke, the same way omnia feeders do.
+// A zero Spread or Heartbeat disables the respective trigger; when both are disabled every round pokes.
+type Policy struct {
+	Spread    float64       // minimum deviation in percent
+	Heartbeat time.Duration // maximum age of the median value
+}
+
+// Decision is the outcome of Evaluate together with a human readable reason.
+type Decision struct {
+	Poke   bool
+	Reason string
+}
+
+// Enabled reports whether any trigger is configured.
+func (policy Policy) Enabled() bool {
+	return 0 < policy.Spread || 0 < policy.Heartbeat
+}
+
+// Evaluate compares the next price with the median state read from chain.
+// current is zero when the median has no valid value yet.
+func (policy Policy) Evaluate(current *big.Int, age time.Time, next *big.Int, now time.Time) Decision {
+	if !policy.Enabled() {
+		return Decision{true, "no policy"}
+	}
+
+	if current == nil || current.Sign() == 0 {
+		return Decision{true, "median has no value"}
+	}
+
+	if 0 < policy.Heartbeat {
+		elapsed := now.Sub(age)
+		if policy.Heartbeat <= elapsed {
+			return Decision{true, fmt.Sprintf("heartbeat: last update %s ago", elapsed.Truncate(time.Second))}
+		}
+	}
+
+	deviation := Deviation(current, next)
+	if 0 < policy.Spread {
+		if spread := new(big.Float).SetFloat64(policy.Spread); deviation.Cmp(spread) > 0 {
+			return Decision{true, fmt.Sprintf("spread: %s%% deviation", deviation.Text('f', 4))}
+		}
+	}
+
+	return Decision{false, fmt.Sprintf("%s%% deviation within spread", deviation.Text('f', 4))}
+}
+
+// Deviation returns |next - current| / current in percent.
+func Deviation(current *big.Int, next *big.Int) *big.Float {
+	if current.Sign() == 0 {
+		return new(big.Float).SetInf(false)
+	}
+
+	diff := new(big.Int).Sub(next, current)
+	diff.Abs(diff).Mul(diff, big.NewInt(100))
+
+	ratio := new(big.Rat).SetFrac(diff, current)
+
+	return new(big.Float).SetRat(ratio)
+}
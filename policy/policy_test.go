@@ -0,0 +1,65 @@
+package policy
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	hour := time.Hour
+
+	tests := []struct {
+		name    string
+		policy  Policy
+		current *big.Int
+		age     time.Duration // since the last update
+		next    int64
+		want    bool
+	}{
+		{"both triggers disabled", Policy{}, big.NewInt(100), 0, 100, true},
+		{"no value yet", Policy{Spread: 1, Heartbeat: hour}, nil, 0, 100, true},
+		{"zero value", Policy{Spread: 1, Heartbeat: hour}, big.NewInt(0), 0, 100, true},
+		{"heartbeat not elapsed", Policy{Heartbeat: hour}, big.NewInt(100), hour - time.Second, 100, false},
+		{"heartbeat just elapsed", Policy{Heartbeat: hour}, big.NewInt(100), hour, 100, true},
+		{"heartbeat elapsed within spread", Policy{Spread: 1, Heartbeat: hour}, big.NewInt(100), 2 * hour, 100, true},
+		{"spread below threshold", Policy{Spread: 1}, big.NewInt(1000), 0, 1009, false},
+		{"spread at threshold", Policy{Spread: 1}, big.NewInt(100), 0, 101, false},
+		{"spread at threshold downward", Policy{Spread: 1}, big.NewInt(100), 0, 99, false},
+		{"spread above threshold", Policy{Spread: 1}, big.NewInt(1000), 0, 1011, true},
+		{"spread above threshold downward", Policy{Spread: 1}, big.NewInt(1000), 0, 989, true},
+		{"heartbeat disabled", Policy{Spread: 1}, big.NewInt(100), 100 * hour, 100, false},
+	}
+
+	for _, test := range tests {
+		decision := test.policy.Evaluate(test.current, now.Add(-test.age), big.NewInt(test.next), now)
+		if decision.Poke != test.want {
+			t.Errorf("%s: poke %t (%s), want %t", test.name, decision.Poke, decision.Reason, test.want)
+		}
+	}
+}
+
+func TestDeviation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		current int64
+		next    int64
+		want    string
+	}{
+		{100, 100, "0.0000"},
+		{100, 101, "1.0000"},
+		{100, 99, "1.0000"},
+		{3, 4, "33.3333"},
+		{0, 1, "+Inf"},
+	}
+
+	for _, test := range tests {
+		if got := Deviation(big.NewInt(test.current), big.NewInt(test.next)).Text('f', 4); got != test.want {
+			t.Errorf("deviation %d -> %d: got %s, want %s", test.current, test.next, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package source
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tgfukuda/test-feed/util"
+)
+
+var errReadFile = errors.New("failed to read price file")
+
+// File reads the price from a local JSON document and reloads it whenever the file changes.
+type File struct {
+	path     string
+	jsonPath string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	price   *big.Int
+}
+
+func NewFile(path string, jsonPath string) (*File, error) {
+	file := &File{path: path, jsonPath: jsonPath}
+	if _, err := file.Price(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (file *File) Price(_ time.Time) (*big.Int, error) {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	info, err := os.Stat(file.path)
+	if err != nil {
+		return nil, util.ChainError(errReadFile, err)
+	}
+
+	if file.price == nil || !info.ModTime().Equal(file.modTime) || info.Size() != file.size {
+		raw, err := os.ReadFile(file.path)
+		if err != nil {
+			return nil, util.ChainError(errReadFile, err)
+		}
+		price, err := parseJson(raw, file.jsonPath)
+		if err != nil {
+			return nil, err
+		}
+		file.price, file.modTime, file.size = price, info.ModTime(), info.Size()
+	}
+
+	return new(big.Int).Set(file.price), nil
+}
+
+func (file *File) Close() error {
+	return nil
+}
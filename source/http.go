@@ -0,0 +1,53 @@
+package source
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/tgfukuda/test-feed/util"
+)
+
+var errFetchPrice = errors.New("failed to fetch price")
+
+// Http fetches the price from a JSON endpoint on every call.
+type Http struct {
+	url      string
+	jsonPath string
+	client   *http.Client
+}
+
+func NewHttp(url string, jsonPath string) (*Http, error) {
+	return &Http{
+		url:      url,
+		jsonPath: jsonPath,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (src *Http) Price(_ time.Time) (*big.Int, error) {
+	res, err := src.client.Get(src.url)
+	if err != nil {
+		return nil, util.ChainError(errFetchPrice, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || 300 <= res.StatusCode {
+		return nil, util.ChainError(errFetchPrice, fmt.Errorf("%s returned %s", src.url, res.Status))
+	}
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, util.ChainError(errFetchPrice, err)
+	}
+
+	return parseJson(raw, src.jsonPath)
+}
+
+func (src *Http) Close() error {
+	src.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,134 @@
+package source
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tgfukuda/test-feed/util"
+)
+
+// Source provides the price pushed to the Median, scaled to WAD (1e18).
+type Source interface {
+	Price(ts time.Time) (*big.Int, error)
+	Close() error
+}
+
+// errors
+var (
+	errUnknownSource = errors.New("unknown price source")
+	errParsePrice    = errors.New("failed to parse price")
+	errNegativePrice = errors.New("price must not be negative")
+	errPriceTooLarge = errors.New("price must be below 2^128 in wad, the largest value the Median stores")
+	errNoPrice       = errors.New("no price available yet")
+)
+
+func errJsonPath(path string) error {
+	return fmt.Errorf("failed to resolve json path %s", path)
+}
+
+var wad = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// maxWad is the largest value of the uint128 the Median stores.
+var maxWad = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// New builds a source from spec. Accepted forms are
+//
+//	static:<price>
+//	file:<path>
+//	http://... or https://...
+//	stdin
+//
+// jsonPath selects the price inside JSON documents for the file and http sources.
+func New(spec string, jsonPath string) (Source, error) {
+	switch {
+	case strings.HasPrefix(spec, "static:"):
+		return NewStatic(strings.TrimPrefix(spec, "static:"))
+	case strings.HasPrefix(spec, "file:"):
+		return NewFile(strings.TrimPrefix(spec, "file:"), jsonPath)
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return NewHttp(spec, jsonPath)
+	case spec == "stdin" || spec == "-":
+		return NewStdin(), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", errUnknownSource, spec)
+}
+
+// ParseWad converts a decimal string such as "1234.5" or "1.2e3" into a WAD scaled integer.
+// Digits beyond 18 decimals are truncated, and values the Median cannot store are rejected.
+func ParseWad(raw string) (*big.Int, error) {
+	rat, ok := new(big.Rat).SetString(strings.TrimSpace(raw))
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errParsePrice, raw)
+	}
+	if rat.Sign() < 0 {
+		return nil, errNegativePrice
+	}
+
+	num := new(big.Int).Mul(rat.Num(), wad)
+	num.Quo(num, rat.Denom())
+	if maxWad.Cmp(num) < 0 {
+		return nil, fmt.Errorf("%w: %q", errPriceTooLarge, raw)
+	}
+
+	return num, nil
+}
+
+// parseJson decodes a JSON document and extracts the price at path.
+// path is dot separated, array elements are addressed by index (e.g. "data.0.price").
+func parseJson(raw []byte, path string) (*big.Int, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, util.ChainError(errParsePrice, err)
+	}
+
+	value, err := lookup(doc, path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		return ParseWad(v.String())
+	case string:
+		return ParseWad(v)
+	}
+
+	return nil, util.ChainError(errJsonPath(path), util.ErrCast)
+}
+
+func lookup(doc interface{}, path string) (interface{}, error) {
+	if path == "" {
+		return doc, nil
+	}
+
+	current := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, ok := node[key]
+			if !ok {
+				return nil, util.ChainError(errJsonPath(path), fmt.Errorf("missing key %s", key))
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || len(node) <= index {
+				return nil, util.ChainError(errJsonPath(path), fmt.Errorf("invalid index %s", key))
+			}
+			current = node[index]
+		default:
+			return nil, util.ChainError(errJsonPath(path), fmt.Errorf("cannot descend into %s", key))
+		}
+	}
+
+	return current, nil
+}
@@ -0,0 +1,28 @@
+package source
+
+import (
+	"math/big"
+	"time"
+)
+
+// Static always returns the same price.
+type Static struct {
+	price *big.Int
+}
+
+func NewStatic(raw string) (*Static, error) {
+	price, err := ParseWad(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Static{price: price}, nil
+}
+
+func (static *Static) Price(_ time.Time) (*big.Int, error) {
+	return new(big.Int).Set(static.price), nil
+}
+
+func (static *Static) Close() error {
+	return nil
+}
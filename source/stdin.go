@@ -0,0 +1,59 @@
+package source
+
+import (
+	"bufio"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// Stdin keeps the latest valid price read line by line from a reader, os.Stdin by default.
+type Stdin struct {
+	mu    sync.Mutex
+	price *big.Int
+	err   error
+}
+
+func NewStdin() *Stdin {
+	return NewReader(os.Stdin)
+}
+
+func NewReader(reader io.Reader) *Stdin {
+	src := &Stdin{err: errNoPrice}
+
+	go func() {
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			if len(scanner.Bytes()) == 0 {
+				continue
+			}
+			price, err := ParseWad(scanner.Text())
+			src.mu.Lock()
+			if err == nil {
+				src.price, src.err = price, nil
+			} else if src.price == nil {
+				src.err = err
+			}
+			src.mu.Unlock()
+		}
+	}()
+
+	return src
+}
+
+func (src *Stdin) Price(_ time.Time) (*big.Int, error) {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	if src.price == nil {
+		return nil, src.err
+	}
+
+	return new(big.Int).Set(src.price), nil
+}
+
+func (src *Stdin) Close() error {
+	return nil
+}
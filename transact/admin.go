@@ -0,0 +1,267 @@
+package transact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+//errors
+var (
+	errGetWards = errors.New("failed to get wards")
+	errGetState = errors.New("failed to read the state back")
+)
+
+func errInvalidHop(hop time.Duration) error {
+	return fmt.Errorf("hop %s must be between 1s and %ds", hop, math.MaxUint16)
+}
+
+func errNotWard(kind string, address common.Address, from common.Address) error {
+	return fmt.Errorf("%s is not a ward of the %s at %s", from.Hex(), kind, address.Hex())
+}
+
+func errNotApplied(method string, state string) error {
+	return fmt.Errorf("%s was mined but %s", method, state)
+}
+
+// wardsReader is the wards getter of the bindings.
+type wardsReader func(opts *bind.CallOpts, usr common.Address) (*big.Int, error)
+
+// requireWard fails unless the sender may call auth methods of the contract.
+func (oracle *Oracle) requireWard(ctx context.Context, kind string, address common.Address, wards wardsReader) error {
+	ward, err := wards(oracle.callOpts(ctx), oracle.from)
+	if err != nil {
+		return util.ChainError(errGetWards, err)
+	}
+	if ward.Cmp(common.Big1) != 0 {
+		return errNotWard(kind, address, oracle.from)
+	}
+
+	return nil
+}
+
+func (oracle *Oracle) requireMedianWard(ctx context.Context) error {
+	return oracle.requireWard(ctx, "Median", oracle.median.address, oracle.median.Wards)
+}
+
+func (oracle *Oracle) requireOsmWard(ctx context.Context) error {
+	if oracle.osm == nil {
+		return errNoOsm
+	}
+	return oracle.requireWard(ctx, "OSM", oracle.osm.address, oracle.osm.Wards)
+}
+
+// expectEach reads flag for every address and fails on the first one different from want.
+func expectEach(method string, want int64, addresses []common.Address, flag func(common.Address) (*big.Int, error)) error {
+	for _, address := range addresses {
+		value, err := flag(address)
+		if err != nil {
+			return util.ChainError(errGetState, err)
+		}
+		if value.Cmp(big.NewInt(want)) != 0 {
+			return errNotApplied(method, fmt.Sprintf("%s is %s", address.Hex(), value))
+		}
+	}
+
+	return nil
+}
+
+// Lift authorizes feeders to sign observations for the Median.
+func (oracle *Oracle) Lift(ctx context.Context, feeders ...common.Address) (*TxResult, error) {
+	if err := oracle.requireMedianWard(ctx); err != nil {
+		return nil, err
+	}
+	result, err := oracle.send(ctx, oracle.median.contract, "lift", feeders)
+	if err != nil {
+		return result, err
+	}
+
+	return result, expectEach("lift", 1, feeders, func(feeder common.Address) (*big.Int, error) {
+		return oracle.median.Orcl(oracle.callOpts(ctx), feeder)
+	})
+}
+
+// Drop revokes feeders from the Median.
+func (oracle *Oracle) Drop(ctx context.Context, feeders ...common.Address) (*TxResult, error) {
+	if err := oracle.requireMedianWard(ctx); err != nil {
+		return nil, err
+	}
+	result, err := oracle.send(ctx, oracle.median.contract, "drop", feeders)
+	if err != nil {
+		return result, err
+	}
+
+	return result, expectEach("drop", 0, feeders, func(feeder common.Address) (*big.Int, error) {
+		return oracle.median.Orcl(oracle.callOpts(ctx), feeder)
+	})
+}
+
+// SetBar sets the number of observations each Median poke must carry.
+func (oracle *Oracle) SetBar(ctx context.Context, bar uint64) (*TxResult, error) {
+	if err := oracle.requireMedianWard(ctx); err != nil {
+		return nil, err
+	}
+	result, err := oracle.send(ctx, oracle.median.contract, "setBar", new(big.Int).SetUint64(bar))
+	if err != nil {
+		return result, err
+	}
+
+	current, err := oracle.GetBar(ctx)
+	if err != nil {
+		return result, util.ChainError(errGetState, err)
+	}
+	if !current.IsUint64() || current.Uint64() != bar {
+		return result, errNotApplied("setBar", fmt.Sprintf("bar is %s", current))
+	}
+
+	return result, nil
+}
+
+// KissMedian allows readers to read the Median value.
+func (oracle *Oracle) KissMedian(ctx context.Context, readers ...common.Address) (*TxResult, error) {
+	if err := oracle.requireMedianWard(ctx); err != nil {
+		return nil, err
+	}
+	result, err := oracle.send(ctx, oracle.median.contract, "kiss0", readers)
+	if err != nil {
+		return result, err
+	}
+
+	return result, expectEach("kiss", 1, readers, func(reader common.Address) (*big.Int, error) {
+		return oracle.median.Bud(oracle.callOpts(ctx), reader)
+	})
+}
+
+// DissMedian stops readers from reading the Median value.
+func (oracle *Oracle) DissMedian(ctx context.Context, readers ...common.Address) (*TxResult, error) {
+	if err := oracle.requireMedianWard(ctx); err != nil {
+		return nil, err
+	}
+	result, err := oracle.send(ctx, oracle.median.contract, "diss0", readers)
+	if err != nil {
+		return result, err
+	}
+
+	return result, expectEach("diss", 0, readers, func(reader common.Address) (*big.Int, error) {
+		return oracle.median.Bud(oracle.callOpts(ctx), reader)
+	})
+}
+
+// KissOsm allows readers to read the OSM values.
+func (oracle *Oracle) KissOsm(ctx context.Context, readers ...common.Address) (*TxResult, error) {
+	if err := oracle.requireOsmWard(ctx); err != nil {
+		return nil, err
+	}
+	result, err := oracle.send(ctx, oracle.osm.contract, "kiss0", readers)
+	if err != nil {
+		return result, err
+	}
+
+	return result, expectEach("kiss", 1, readers, func(reader common.Address) (*big.Int, error) {
+		return oracle.osm.Bud(oracle.callOpts(ctx), reader)
+	})
+}
+
+// DissOsm stops readers from reading the OSM values.
+func (oracle *Oracle) DissOsm(ctx context.Context, readers ...common.Address) (*TxResult, error) {
+	if err := oracle.requireOsmWard(ctx); err != nil {
+		return nil, err
+	}
+	result, err := oracle.send(ctx, oracle.osm.contract, "diss0", readers)
+	if err != nil {
+		return result, err
+	}
+
+	return result, expectEach("diss", 0, readers, func(reader common.Address) (*big.Int, error) {
+		return oracle.osm.Bud(oracle.callOpts(ctx), reader)
+	})
+}
+
+// StopOsm freezes the OSM so that it cannot be poked.
+func (oracle *Oracle) StopOsm(ctx context.Context) (*TxResult, error) {
+	return oracle.setStopped(ctx, "stop", 1)
+}
+
+// StartOsm lets a stopped OSM be poked again.
+func (oracle *Oracle) StartOsm(ctx context.Context) (*TxResult, error) {
+	return oracle.setStopped(ctx, "start", 0)
+}
+
+// VoidOsm clears both OSM values and stops it.
+func (oracle *Oracle) VoidOsm(ctx context.Context) (*TxResult, error) {
+	return oracle.setStopped(ctx, "void", 1)
+}
+
+func (oracle *Oracle) setStopped(ctx context.Context, method string, stopped int64) (*TxResult, error) {
+	if err := oracle.requireOsmWard(ctx); err != nil {
+		return nil, err
+	}
+	result, err := oracle.send(ctx, oracle.osm.contract, method)
+	if err != nil {
+		return result, err
+	}
+
+	current, err := oracle.osm.Stopped(oracle.callOpts(ctx))
+	if err != nil {
+		return result, util.ChainError(errGetState, err)
+	}
+	if current.Cmp(big.NewInt(stopped)) != 0 {
+		return result, errNotApplied(method, fmt.Sprintf("stopped is %s", current))
+	}
+
+	return result, nil
+}
+
+// ChangeOsm makes the OSM read from another source.
+// The oracle keeps poking the Median it was created with.
+func (oracle *Oracle) ChangeOsm(ctx context.Context, src common.Address) (*TxResult, error) {
+	if err := oracle.requireOsmWard(ctx); err != nil {
+		return nil, err
+	}
+	result, err := oracle.send(ctx, oracle.osm.contract, "change", src)
+	if err != nil {
+		return result, err
+	}
+
+	current, err := oracle.osm.Src(oracle.callOpts(ctx))
+	if err != nil {
+		return result, util.ChainError(errGetState, err)
+	}
+	if current != src {
+		return result, errNotApplied("change", "src is "+current.Hex())
+	}
+
+	return result, nil
+}
+
+// SetHop sets the delay between OSM pokes, in whole seconds.
+func (oracle *Oracle) SetHop(ctx context.Context, hop time.Duration) (*TxResult, error) {
+	seconds := hop / time.Second
+	if seconds <= 0 || math.MaxUint16 < seconds {
+		return nil, errInvalidHop(hop)
+	}
+	if err := oracle.requireOsmWard(ctx); err != nil {
+		return nil, err
+	}
+	result, err := oracle.send(ctx, oracle.osm.contract, "step", uint16(seconds))
+	if err != nil {
+		return result, err
+	}
+
+	_, current, err := oracle.GetOsmSchedule(ctx)
+	if err != nil {
+		return result, util.ChainError(errGetState, err)
+	}
+	if current != seconds*time.Second {
+		return result, errNotApplied("step", "hop is "+current.String())
+	}
+
+	return result, nil
+}
@@ -0,0 +1,26 @@
+package transact
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// Backend is everything the oracle needs from a chain connection.
+type Backend interface {
+	bind.ContractBackend
+	bind.PendingContractCaller
+	ethereum.ChainReader
+	ethereum.ChainStateReader
+	ethereum.TransactionReader
+
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// rawCaller is implemented by backends giving access to arbitrary rpc methods such as debug_traceTransaction.
+type rawCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
@@ -0,0 +1,121 @@
+package transact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+//errors
+var (
+	errGetReceipt = errors.New("failed to get receipt")
+	errTimeout    = errors.New("timed out waiting for transaction")
+)
+
+func errReorged(hash common.Hash, block common.Hash) error {
+	return fmt.Errorf("transaction %s was dropped from block %s by a reorg", hash.Hex(), block.Hex())
+}
+
+// ConfirmConfig controls how long a sent transaction is waited for.
+type ConfirmConfig struct {
+	Confirmations uint64        // blocks required on top of the inclusion block
+	Timeout       time.Duration // overall wait from submission. 0 waits forever
+}
+
+var DefaultConfirmConfig = ConfirmConfig{
+	Confirmations: 0,
+	Timeout:       10 * time.Minute,
+}
+
+// SetConfirmConfig replaces the confirmation settings.
+func (oracle *Oracle) SetConfirmConfig(config ConfirmConfig) {
+	oracle.confirmConfig = config
+}
+
+// TxResult describes a mined transaction.
+type TxResult struct {
+	Tx          *types.Transaction
+	Receipt     *types.Receipt
+	BlockNumber *big.Int    // inclusion block
+	BlockHash   common.Hash // inclusion block
+}
+
+// Hash returns the hash of the transaction.
+func (result *TxResult) Hash() common.Hash {
+	return result.Tx.Hash()
+}
+
+// unmined wraps a transaction whose inclusion is unknown. nil if it was never sent.
+func unmined(tx *types.Transaction) *TxResult {
+	if tx == nil {
+		return nil
+	}
+
+	return &TxResult{Tx: tx}
+}
+
+// confirm waits for the receipt of tx and the configured number of blocks on top of it.
+// The inclusion block is re-checked against the canonical chain, so a receipt dropped by a reorg is waited for again.
+func (oracle *Oracle) confirm(ctx context.Context, tx *types.Transaction) (*TxResult, error) {
+	var seen, dropped *types.Receipt
+	for {
+		receipt, err := oracle.backend.TransactionReceipt(ctx, tx.Hash())
+		switch {
+		case errors.Is(err, ethereum.NotFound):
+			if seen != nil {
+				oracle.logger.Warn("transaction dropped by a reorg", "hash", tx.Hash(), "block", seen.BlockHash)
+				seen, dropped = nil, seen
+			}
+		case err != nil:
+			if ctx.Err() == nil {
+				oracle.logger.Warn(errGetReceipt.Error(), "hash", tx.Hash(), "err", err)
+			}
+		default:
+			seen = receipt
+			head, err := oracle.backend.BlockNumber(ctx)
+			if err != nil {
+				break
+			}
+			included := receipt.BlockNumber.Uint64()
+			if head < included+oracle.confirmConfig.Confirmations {
+				break
+			}
+
+			header, err := oracle.backend.HeaderByNumber(ctx, receipt.BlockNumber)
+			if err != nil {
+				break
+			}
+			if header.Hash() != receipt.BlockHash {
+				oracle.logger.Warn("transaction dropped by a reorg", "hash", tx.Hash(), "block", receipt.BlockHash)
+				seen, dropped = nil, receipt
+				break
+			}
+
+			return &TxResult{
+				Tx:          tx,
+				Receipt:     receipt,
+				BlockNumber: receipt.BlockNumber,
+				BlockHash:   receipt.BlockHash,
+			}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if dropped != nil {
+				return nil, errReorged(tx.Hash(), dropped.BlockHash)
+			}
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, util.ChainError(errTimeout, ctx.Err())
+			}
+			return nil, util.ChainError(errCanceled, ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package transact
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/tgfukuda/test-feed/contracts"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+//errors
+var (
+	errNoContractAddress = errors.New("no contract address in the receipt")
+	errEmbeddedWat       = errors.New("failed to read the wat of the embedded Median")
+)
+
+func errDeploy(kind string) error {
+	return fmt.Errorf("failed to deploy %s", kind)
+}
+
+func errNoBytecode(kind string) error {
+	return fmt.Errorf("no %s bytecode embedded. build it with deploy-oracle.sh and EMBED_BIN=true, then go build again", kind)
+}
+
+// DeployMedian creates the embedded Median. Its wat is fixed by the contract built, e.g. MedianETHUSD. The sender becomes its ward.
+func (oracle *Oracle) DeployMedian(ctx context.Context) (common.Address, *TxResult, error) {
+	return oracle.deploy(ctx, "Median", contracts.MedianMetaData)
+}
+
+// EmbeddedWat returns the wat of the embedded Median, read by running its constructor in memory without any chain.
+func EmbeddedWat() (string, error) {
+	meta := contracts.MedianMetaData
+	if meta.Bin == "" {
+		return "", errNoBytecode("Median")
+	}
+	parsed, err := meta.GetAbi()
+	if err != nil {
+		return "", util.ChainError(errParseAbi, err)
+	}
+	input, err := parsed.Pack("wat")
+	if err != nil {
+		return "", util.ChainError(errPackCall, err)
+	}
+
+	cfg := new(runtime.Config)
+	_, address, _, err := runtime.Create(common.FromHex(meta.Bin), cfg)
+	if err != nil {
+		return "", util.ChainError(errEmbeddedWat, err)
+	}
+	output, _, err := runtime.Call(address, input, cfg)
+	if err != nil {
+		return "", util.ChainError(errEmbeddedWat, err)
+	}
+	wat, err := parsed.Unpack("wat", output)
+	if err != nil {
+		return "", util.ChainError(errEmbeddedWat, err)
+	}
+	label, ok := wat[0].([32]byte)
+	if !ok {
+		return "", errEmbeddedWat
+	}
+
+	return string(bytes.TrimRight(label[:], "\x00")), nil
+}
+
+// DeployOSM creates an OSM delaying the value of median. The sender becomes its ward.
+func (oracle *Oracle) DeployOSM(ctx context.Context, median common.Address) (common.Address, *TxResult, error) {
+	return oracle.deploy(ctx, "OSM", contracts.OSMMetaData, median)
+}
+
+func (oracle *Oracle) deploy(ctx context.Context, kind string, meta *bind.MetaData, args ...interface{}) (common.Address, *TxResult, error) {
+	if meta.Bin == "" {
+		return common.Address{}, nil, errNoBytecode(kind)
+	}
+	parsed, err := meta.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, util.ChainError(errParseAbi, err)
+	}
+	input, err := parsed.Pack("", args...)
+	if err != nil {
+		return common.Address{}, nil, util.ChainError(errPackCall, err)
+	}
+
+	oracle.logger.Info("deploying", "contract", kind)
+	result, err := oracle.sendMessage(ctx, message{
+		name: "deploy " + kind,
+		data: append(common.FromHex(meta.Bin), input...),
+	})
+	if err != nil {
+		return common.Address{}, result, util.ChainError(errDeploy(kind), err)
+	}
+	address := result.Receipt.ContractAddress
+	if address == (common.Address{}) {
+		return common.Address{}, result, util.ChainError(errDeploy(kind), errNoContractAddress)
+	}
+	oracle.logger.Info("deployed", "contract", kind, "address", address)
+
+	return address, result, nil
+}
@@ -0,0 +1,138 @@
+package transact
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+//errors
+var (
+	errFilterLogs = errors.New("failed to filter logs")
+	errParseLog   = errors.New("failed to parse log")
+)
+
+// the contract of a PriceUpdate
+const (
+	MedianContract = "median"
+	OsmContract    = "osm"
+)
+
+// PriceUpdate is a value logged by the Median (LogMedianPrice) or by the OSM (LogValue, its new current value).
+type PriceUpdate struct {
+	Contract    string
+	Price       *big.Int
+	BlockNumber uint64
+	TxHash      common.Hash
+	index       uint // log index in the block, for ordering
+}
+
+// WatchPrices sends the updates logged from block from onward to updates, in chain order, until ctx is canceled.
+// Logs are read on every new head where the endpoint supports subscriptions, and every interval otherwise.
+func (oracle *Oracle) WatchPrices(ctx context.Context, from uint64, interval time.Duration, updates chan<- PriceUpdate) error {
+	heads := make(chan *types.Header, 16)
+	var tick <-chan time.Time
+	var subErr <-chan error
+	sub, err := oracle.SubscribeHeads(ctx, heads)
+	switch {
+	case errors.Is(err, errNoSubscription):
+		oracle.logger.Debug("polling logs", "interval", interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	case err != nil:
+		return err
+	default:
+		defer sub.Unsubscribe()
+		subErr = sub.Err()
+	}
+
+	for {
+		head, err := oracle.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+
+		if from <= head {
+			found, err := oracle.priceUpdates(ctx, from, head)
+			if err != nil {
+				return err
+			}
+			for _, update := range found {
+				select {
+				case updates <- update:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+			from = head + 1
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-subErr:
+			return util.ChainError(errSubscribe, err)
+		case <-heads:
+		case <-tick:
+		}
+	}
+}
+
+// priceUpdates reads the updates logged between the blocks start and end inclusive.
+func (oracle *Oracle) priceUpdates(ctx context.Context, start uint64, end uint64) ([]PriceUpdate, error) {
+	opts := &bind.FilterOpts{Start: start, End: &end, Context: ctx}
+	var updates []PriceUpdate
+
+	medianLogs, err := oracle.median.FilterLogMedianPrice(opts)
+	if err != nil {
+		return nil, util.ChainError(errFilterLogs, err)
+	}
+	defer medianLogs.Close()
+	for medianLogs.Next() {
+		updates = append(updates, newPriceUpdate(MedianContract, medianLogs.Event.Val, medianLogs.Event.Raw))
+	}
+	if err := medianLogs.Error(); err != nil {
+		return nil, util.ChainError(errParseLog, err)
+	}
+
+	if oracle.osm != nil {
+		osmLogs, err := oracle.osm.FilterLogValue(opts)
+		if err != nil {
+			return nil, util.ChainError(errFilterLogs, err)
+		}
+		defer osmLogs.Close()
+		for osmLogs.Next() {
+			updates = append(updates, newPriceUpdate(OsmContract, new(big.Int).SetBytes(osmLogs.Event.Val[:]), osmLogs.Event.Raw))
+		}
+		if err := osmLogs.Error(); err != nil {
+			return nil, util.ChainError(errParseLog, err)
+		}
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		if updates[i].BlockNumber != updates[j].BlockNumber {
+			return updates[i].BlockNumber < updates[j].BlockNumber
+		}
+		return updates[i].index < updates[j].index
+	})
+
+	return updates, nil
+}
+
+func newPriceUpdate(contract string, price *big.Int, raw types.Log) PriceUpdate {
+	return PriceUpdate{
+		Contract:    contract,
+		Price:       price,
+		BlockNumber: raw.BlockNumber,
+		TxHash:      raw.TxHash,
+		index:       raw.Index,
+	}
+}
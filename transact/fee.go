@@ -0,0 +1,63 @@
+package transact
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+//errors
+var (
+	errCalcTip      = errors.New("failed to calculate gas tip cap")
+	errFeeCapTooLow = errors.New("fee cap is lower than tip cap")
+)
+
+// FeeConfig controls how transaction fees are priced.
+// Nil values are filled with the node's suggestions.
+type FeeConfig struct {
+	TipCap *big.Int // max priority fee per gas
+	FeeCap *big.Int // max fee per gas. defaults to 2 * base fee + tip cap
+}
+
+// SetFeeConfig replaces the fee settings used for every transaction sent by the oracle.
+func (oracle *Oracle) SetFeeConfig(config FeeConfig) {
+	oracle.fee = config
+}
+
+// applyFees prices auth as a dynamic fee transaction,
+// falling back to a legacy gas price on chains without London (no base fee in the latest header).
+func (oracle *Oracle) applyFees(ctx context.Context, head *types.Header, auth *bind.TransactOpts) (err error) {
+	if head.BaseFee == nil {
+		gasPrice, err := oracle.backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return util.ChainError(errCalcGas, err)
+		}
+		auth.GasPrice = gasPrice
+		return nil
+	}
+
+	tip := oracle.fee.TipCap
+	if tip == nil {
+		tip, err = oracle.backend.SuggestGasTipCap(ctx)
+		if err != nil {
+			return util.ChainError(errCalcTip, err)
+		}
+	}
+
+	feeCap := oracle.fee.FeeCap
+	if feeCap == nil {
+		feeCap = new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tip)
+	}
+	if feeCap.Cmp(tip) < 0 {
+		return errFeeCapTooLow
+	}
+
+	auth.GasTipCap = tip
+	auth.GasFeeCap = feeCap
+
+	return nil
+}
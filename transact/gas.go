@@ -0,0 +1,79 @@
+package transact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+//errors
+var (
+	errPackCall    = errors.New("failed to pack calldata")
+	errEstimateGas = errors.New("failed to estimate gas")
+)
+
+func errPredictedRevert(method string, revert *RevertError) error {
+	return fmt.Errorf("%s is predicted to revert: %w", method, revert)
+}
+
+// GasConfig controls the gas limit derived from eth_estimateGas.
+type GasConfig struct {
+	Multiplier float64 // applied to the estimate. 1 if not positive
+	Ceiling    uint64  // upper bound of the gas limit. the block gas limit if 0
+}
+
+// DefaultGasConfig leaves some headroom over the estimate.
+var DefaultGasConfig = GasConfig{Multiplier: 1.25}
+
+// SetGasConfig replaces the gas limit settings used for every transaction sent by the oracle.
+func (oracle *Oracle) SetGasConfig(config GasConfig) {
+	oracle.gas = config
+}
+
+// estimateGas sets the gas limit of auth from an estimate of the exact message being sent.
+// An estimate failing with revert data is reported as a predicted revert.
+func (oracle *Oracle) estimateGas(ctx context.Context, head *types.Header, auth *bind.TransactOpts, msg message) error {
+	estimate, err := oracle.backend.EstimateGas(ctx, ethereum.CallMsg{
+		From:      auth.From,
+		To:        msg.to,
+		GasPrice:  auth.GasPrice,
+		GasFeeCap: auth.GasFeeCap,
+		GasTipCap: auth.GasTipCap,
+		Value:     auth.Value,
+		Data:      msg.data,
+	})
+	if err != nil {
+		if revert, ok := asRevert(err); ok {
+			return errPredictedRevert(msg.name, revert)
+		}
+		return util.ChainError(errEstimateGas, err)
+	}
+
+	multiplier := oracle.gas.Multiplier
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+
+	ceiling := oracle.gas.Ceiling
+	if ceiling == 0 || head.GasLimit < ceiling {
+		ceiling = head.GasLimit
+	}
+
+	limit := uint64(math.Ceil(float64(estimate) * multiplier))
+	if ceiling < limit {
+		limit = ceiling
+	}
+	if limit < estimate {
+		return util.ChainError(errEstimateGas, fmt.Errorf("estimate %d exceeds the ceiling %d", estimate, ceiling))
+	}
+
+	auth.GasLimit = limit
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package transact
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+//errors
+var (
+	errNoSubscription = errors.New("endpoint does not support subscriptions. use ws, wss or ipc")
+	errSubscribe      = errors.New("failed to subscribe new heads")
+)
+
+// SubscribeHeads streams the headers of new blocks. Only ws, wss and ipc endpoints support it.
+func (oracle *Oracle) SubscribeHeads(ctx context.Context, heads chan<- *types.Header) (ethereum.Subscription, error) {
+	sub, err := oracle.backend.SubscribeNewHead(ctx, heads)
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		return nil, errNoSubscription
+	}
+	if err != nil {
+		return nil, util.ChainError(errSubscribe, err)
+	}
+
+	return sub, nil
+}
@@ -0,0 +1,235 @@
+package transact
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+// historyChunk bounds the block range of a single eth_getLogs, which most providers limit
+const historyChunk = 2000
+
+//errors
+var (
+	errGetTx      = errors.New("failed to get transaction")
+	errDecodePoke = errors.New("failed to decode poke calldata")
+	errTxSender   = errors.New("failed to get transaction sender")
+	errNotPoke    = errors.New("calldata does not call poke")
+	errPokeArgs   = errors.New("poke arguments are not vals, ages, v, r and s")
+)
+
+func errPokeLength(vals int, ages int, sigs int) error {
+	return fmt.Errorf("poke has %d vals, %d ages and %d signatures", vals, ages, sigs)
+}
+
+// PokeRecord is a poke sent to the Median, decoded from its calldata and receipt.
+type PokeRecord struct {
+	BlockNumber  uint64
+	Time         time.Time // of the block
+	TxHash       common.Hash
+	From         common.Address
+	Status       uint64
+	GasUsed      uint64
+	Median       *big.Int      // from LogMedianPrice, nil when the poke reverted
+	Observations []Observation // empty when the transaction does not call poke directly
+}
+
+// Observation is a signed price submitted in a poke along with the address recovered from its signature.
+type Observation struct {
+	Val    *big.Int
+	Age    time.Time
+	Signer common.Address
+}
+
+// PokeHistory returns the pokes mined between the blocks start and end inclusive, in chain order.
+// Pokes are found from their LogMedianPrice, so reverted ones are only included when reverted is set,
+// which reads every block of the range instead.
+func (oracle *Oracle) PokeHistory(ctx context.Context, start uint64, end uint64, reverted bool) ([]PokeRecord, error) {
+	chainId, err := oracle.backend.ChainID(ctx)
+	if err != nil {
+		return nil, util.ChainError(errChainId, err)
+	}
+	signer := types.LatestSignerForChainID(chainId)
+
+	var txs []*types.Transaction
+	if reverted {
+		txs, err = oracle.scanPokes(ctx, start, end)
+	} else {
+		txs, err = oracle.loggedPokes(ctx, start, end)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]PokeRecord, 0, len(txs))
+	times := make(map[uint64]time.Time)
+	for _, tx := range txs {
+		receipt, err := oracle.backend.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			return nil, util.ChainError(errGetReceipt, err)
+		}
+		number := receipt.BlockNumber.Uint64()
+		if _, ok := times[number]; !ok {
+			header, err := oracle.backend.HeaderByNumber(ctx, receipt.BlockNumber)
+			if err != nil {
+				return nil, util.ChainError(errGetBlock, err)
+			}
+			times[number] = time.Unix(int64(header.Time), 0)
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, util.ChainError(errTxSender, err)
+		}
+		// a poke relayed through another contract has calldata of its own, which must not abort the whole range
+		observations, err := oracle.decodePoke(tx.Data())
+		if err != nil {
+			oracle.logger.Warn(errDecodePoke.Error(), "hash", tx.Hash(), "err", err)
+		}
+
+		records = append(records, PokeRecord{
+			BlockNumber:  number,
+			Time:         times[number],
+			TxHash:       tx.Hash(),
+			From:         from,
+			Status:       receipt.Status,
+			GasUsed:      receipt.GasUsed,
+			Median:       oracle.loggedMedian(receipt),
+			Observations: observations,
+		})
+	}
+
+	return records, nil
+}
+
+// loggedPokes finds the pokes having emitted a LogMedianPrice, reading the logs in chunks of historyChunk blocks.
+func (oracle *Oracle) loggedPokes(ctx context.Context, start uint64, end uint64) ([]*types.Transaction, error) {
+	var txs []*types.Transaction
+	for from := start; from <= end; from += historyChunk {
+		to := from + historyChunk - 1
+		if end < to {
+			to = end
+		}
+		oracle.logger.Debug("reading median logs", "from", from, "to", to)
+
+		hashes, err := oracle.medianPriceTxs(ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, hash := range hashes {
+			tx, _, err := oracle.backend.TransactionByHash(ctx, hash)
+			if err != nil {
+				return nil, util.ChainError(errGetTx, err)
+			}
+			txs = append(txs, tx)
+		}
+	}
+
+	return txs, nil
+}
+
+// medianPriceTxs returns the transactions having logged a LogMedianPrice between the blocks start and end inclusive, once each.
+func (oracle *Oracle) medianPriceTxs(ctx context.Context, start uint64, end uint64) ([]common.Hash, error) {
+	logs, err := oracle.median.FilterLogMedianPrice(&bind.FilterOpts{Start: start, End: &end, Context: ctx})
+	if err != nil {
+		return nil, util.ChainError(errFilterLogs, err)
+	}
+	defer logs.Close()
+
+	var hashes []common.Hash
+	for logs.Next() {
+		// logs come in chain order, so that those of the same transaction are adjacent
+		hash := logs.Event.Raw.TxHash
+		if len(hashes) == 0 || hashes[len(hashes)-1] != hash {
+			hashes = append(hashes, hash)
+		}
+	}
+	if err := logs.Error(); err != nil {
+		return nil, util.ChainError(errParseLog, err)
+	}
+
+	return hashes, nil
+}
+
+// scanPokes finds the transactions calling poke on the Median in every block of the range, reverted or not.
+func (oracle *Oracle) scanPokes(ctx context.Context, start uint64, end uint64) ([]*types.Transaction, error) {
+	id := oracle.median.abi.Methods["poke"].ID
+
+	var txs []*types.Transaction
+	for number := start; number <= end; number++ {
+		if number%historyChunk == 0 {
+			oracle.logger.Debug("scanning blocks", "from", number, "to", end)
+		}
+		block, err := oracle.backend.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return nil, util.ChainError(errGetBlock, err)
+		}
+		for _, tx := range block.Transactions() {
+			if tx.To() != nil && *tx.To() == oracle.median.address && bytes.HasPrefix(tx.Data(), id) {
+				txs = append(txs, tx)
+			}
+		}
+	}
+
+	return txs, nil
+}
+
+// decodePoke unpacks the poke calldata and recovers the signer of each observation.
+// Signatures not recovering an address leave Signer zero, as the Median would reject them anyway.
+func (oracle *Oracle) decodePoke(data []byte) ([]Observation, error) {
+	method := oracle.median.abi.Methods["poke"]
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return nil, errNotPoke
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != 5 {
+		return nil, errPokeArgs
+	}
+	vals, okVal := args[0].([]*big.Int)
+	ages, okAge := args[1].([]*big.Int)
+	vs, okV := args[2].([]uint8)
+	rs, okR := args[3].([][32]byte)
+	ss, okS := args[4].([][32]byte)
+	if !okVal || !okAge || !okV || !okR || !okS {
+		return nil, errPokeArgs
+	}
+	if len(ages) != len(vals) || len(vs) != len(vals) || len(rs) != len(vals) || len(ss) != len(vals) {
+		return nil, errPokeLength(len(vals), len(ages), len(vs))
+	}
+
+	observations := make([]Observation, len(vals))
+	for i, val := range vals {
+		age := time.Unix(ages[i].Int64(), 0)
+		observations[i] = Observation{Val: val, Age: age}
+		if signer, err := Recover(Prefix(Hash(val, age, oracle.wat)), &rs[i], &ss[i], vs[i]); err == nil {
+			observations[i].Signer = *signer
+		}
+	}
+
+	return observations, nil
+}
+
+// loggedMedian returns the value of the LogMedianPrice in receipt, or nil when there is none.
+func (oracle *Oracle) loggedMedian(receipt *types.Receipt) *big.Int {
+	id := oracle.median.abi.Events["LogMedianPrice"].ID
+	for _, log := range receipt.Logs {
+		if log.Address != oracle.median.address || len(log.Topics) == 0 || log.Topics[0] != id {
+			continue
+		}
+		if event, err := oracle.median.ParseLogMedianPrice(*log); err == nil {
+			return event.Val
+		}
+	}
+
+	return nil
+}
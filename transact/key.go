@@ -8,6 +8,9 @@ import (
 	"io"
 	"math/big"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -54,6 +57,7 @@ var (
 	errInvalidSignature = errors.New("invalid signature")
 	errInvalidId        = errors.New("v must be 27 or 28")
 	errGetPriv          = errors.New("failed to get private key")
+	errReadKeystoreDir  = errors.New("failed to read keystore directory")
 )
 
 func Sign(privKey *ecdsa.PrivateKey, hash []byte) (*[32]byte, *[32]byte, byte, error) {
@@ -123,3 +127,43 @@ func GetPrivFromFile(keyFilePath string, passwordPath string) (*ecdsa.PrivateKey
 
 	return ks.PrivateKey, nil
 }
+
+// GetPrivsFromPaths loads every key in paths with the same password.
+// A path may be a keystore file or a directory, in which case all regular files inside are loaded in name order.
+func GetPrivsFromPaths(paths []string, passwordPath string) ([]*ecdsa.PrivateKey, error) {
+	var keyFiles []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, util.ChainError(errGetPriv, err)
+		}
+		if !info.IsDir() {
+			keyFiles = append(keyFiles, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, util.ChainError(errReadKeystoreDir, err)
+		}
+		var names []string
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+				names = append(names, filepath.Join(path, entry.Name()))
+			}
+		}
+		sort.Strings(names)
+		keyFiles = append(keyFiles, names...)
+	}
+
+	keys := make([]*ecdsa.PrivateKey, 0, len(keyFiles))
+	for _, keyFile := range keyFiles {
+		key, err := GetPrivFromFile(keyFile, passwordPath)
+		if err != nil {
+			return nil, util.ChainError(fmt.Errorf("failed to load %s", keyFile), err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,181 @@
+package transact
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+//errors
+var (
+	errCanceled = errors.New("gave up waiting for transaction")
+	errReplace  = errors.New("failed to replace transaction")
+)
+
+// pollInterval is the delay between checks of a pending transaction
+const pollInterval = time.Second
+
+// NonceConfig controls the replacement of transactions stuck in the mempool.
+type NonceConfig struct {
+	ReplaceTimeout  time.Duration // wait before re-sending with bumped fees. 0 disables replacement
+	FeeBump         uint64        // fee increase in percent. geth requires at least 10
+	MaxReplacements int           // give up replacing after this many attempts
+}
+
+var DefaultNonceConfig = NonceConfig{
+	ReplaceTimeout:  2 * time.Minute,
+	FeeBump:         20,
+	MaxReplacements: 5,
+}
+
+// SetNonceConfig replaces the settings for stuck transactions.
+func (oracle *Oracle) SetNonceConfig(config NonceConfig) {
+	oracle.nonceConfig = config
+}
+
+// NonceManager hands out nonces for one account and tracks the transactions in flight,
+// so that concurrent senders never reuse a nonce before the node sees it as pending.
+// Oracles sending from the same account should share one through SetNonceManager.
+type NonceManager struct {
+	mu       sync.Mutex
+	next     *uint64
+	inflight map[uint64]*types.Transaction
+}
+
+func NewNonceManager() *NonceManager {
+	return &NonceManager{inflight: make(map[uint64]*types.Transaction)}
+}
+
+// acquire returns the next free nonce, never lower than the pending nonce on the node.
+func (manager *NonceManager) acquire(ctx context.Context, backend Backend, from common.Address) (uint64, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	pending, err := backend.PendingNonceAt(ctx, from)
+	if err != nil {
+		return 0, util.ChainError(errCalcNonce, err)
+	}
+
+	nonce := pending
+	if manager.next != nil && pending < *manager.next {
+		nonce = *manager.next
+	}
+	next := nonce + 1
+	manager.next = &next
+
+	return nonce, nil
+}
+
+// reset drops the local counter after a nonce was handed out but never used, so the next acquire resyncs with the node.
+func (manager *NonceManager) reset() {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	manager.next = nil
+}
+
+func (manager *NonceManager) track(tx *types.Transaction) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	manager.inflight[tx.Nonce()] = tx
+}
+
+func (manager *NonceManager) done(nonce uint64) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	delete(manager.inflight, nonce)
+}
+
+// SetNonceManager makes the oracle draw nonces from manager, which must belong to the same sender.
+func (oracle *Oracle) SetNonceManager(manager *NonceManager) {
+	oracle.nonces = manager
+}
+
+// Inflight returns the transactions sent by the oracle which are not mined yet.
+func (oracle *Oracle) Inflight() []*types.Transaction {
+	oracle.nonces.mu.Lock()
+	defer oracle.nonces.mu.Unlock()
+
+	txs := make([]*types.Transaction, 0, len(oracle.nonces.inflight))
+	for _, tx := range oracle.nonces.inflight {
+		txs = append(txs, tx)
+	}
+
+	return txs
+}
+
+// bump raises value by percent, plus one wei so that small values always increase.
+func bump(value *big.Int, percent uint64) *big.Int {
+	if value == nil {
+		return nil
+	}
+
+	bumped := new(big.Int).Mul(value, new(big.Int).SetUint64(100+percent))
+	bumped.Div(bumped, big.NewInt(100))
+
+	return bumped.Add(bumped, common.Big1)
+}
+
+// submit sends the transaction and waits until one transaction with its nonce is mined.
+// When nothing is mined within the replace timeout, the same nonce is re-sent with bumped fees.
+func (oracle *Oracle) submit(ctx context.Context, auth *bind.TransactOpts, msg message) (*types.Transaction, error) {
+	tx, err := oracle.transact(ctx, auth, msg)
+	if err != nil {
+		oracle.nonces.reset()
+		return nil, err
+	}
+	oracle.nonces.track(tx)
+	defer oracle.nonces.done(tx.Nonce())
+
+	sent := []*types.Transaction{tx}
+	config := oracle.nonceConfig
+	deadline := time.Now().Add(config.ReplaceTimeout)
+
+	oracle.logger.Info("sent transaction", "method", msg.name, "hash", tx.Hash(), "nonce", tx.Nonce(), "gas", tx.Gas())
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return sent[len(sent)-1], util.ChainError(errTimeout, ctx.Err())
+			}
+			return sent[len(sent)-1], util.ChainError(errCanceled, ctx.Err())
+		case <-time.After(pollInterval):
+		}
+		oracle.logger.Trace("waiting for transaction", "hash", sent[len(sent)-1].Hash())
+
+		for _, candidate := range sent {
+			_, pending, err := oracle.backend.TransactionByHash(ctx, candidate.Hash())
+			if err == nil && !pending {
+				return candidate, nil
+			}
+		}
+
+		if config.ReplaceTimeout <= 0 || time.Now().Before(deadline) || config.MaxReplacements < len(sent) {
+			continue
+		}
+		deadline = time.Now().Add(config.ReplaceTimeout)
+
+		auth.GasPrice = bump(auth.GasPrice, config.FeeBump)
+		auth.GasTipCap = bump(auth.GasTipCap, config.FeeBump)
+		auth.GasFeeCap = bump(auth.GasFeeCap, config.FeeBump)
+
+		replacement, err := oracle.transact(ctx, auth, msg)
+		if err != nil {
+			// the original may just have been mined, which is picked up by the next poll
+			oracle.logger.Warn(errReplace.Error(), "nonce", auth.Nonce, "err", err)
+			continue
+		}
+		oracle.logger.Info("replaced transaction", "hash", sent[len(sent)-1].Hash(), "replacement", replacement.Hash(), "nonce", replacement.Nonce(), "gasFeeCap", replacement.GasFeeCap(), "gasTipCap", replacement.GasTipCap())
+		sent = append(sent, replacement)
+		oracle.nonces.track(replacement)
+	}
+}
@@ -0,0 +1,403 @@
+package transact
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	h := newHarness(t, 1)
+
+	t.Run("median from osm", func(t *testing.T) {
+		oracle := newTestOracle(t, h.backend, h.owner, Contracts{Osm: h.osm})
+		if !oracle.HasOsm() {
+			t.Error("expected an OSM")
+		}
+		if oracle.median.address != h.median {
+			t.Errorf("median %s, want %s", oracle.median.address.Hex(), h.median.Hex())
+		}
+		// the wat is fixed by the Median built, e.g. MedianETHUSD
+		if oracle.Wat() != h.oracle.Wat() || oracle.Wat() == "" {
+			t.Errorf("wat %q, want %q", oracle.Wat(), h.oracle.Wat())
+		}
+		if err := oracle.ValidateWat(oracle.Wat() + "X"); err == nil {
+			t.Error("expected a wat mismatch")
+		}
+	})
+
+	t.Run("median alone", func(t *testing.T) {
+		oracle := newTestOracle(t, h.backend, h.owner, Contracts{Median: h.median})
+		if oracle.HasOsm() {
+			t.Error("expected no OSM")
+		}
+		if _, err := oracle.PokeOsm(context.Background()); !errors.Is(err, errNoOsm) {
+			t.Errorf("got %v, want %v", err, errNoOsm)
+		}
+	})
+}
+
+func TestPoke(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	h := newHarness(t, 1)
+
+	if _, err := h.oracle.Poke(ctx, constant(1234)); err != nil {
+		t.Fatal(err)
+	}
+
+	price, err := h.oracle.GetMedianPrice(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(1234)) != 0 {
+		t.Errorf("price %s, want 1234", price)
+	}
+
+	_, age, err := h.oracle.GetMedianState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := h.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if age.Unix() != int64(head.Time) {
+		t.Errorf("age %d, want the time of the poke block %d", age.Unix(), head.Time)
+	}
+}
+
+func TestPokeMultipleSigners(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	h := newHarness(t, 3)
+
+	// each signer observes a different price and the Median takes the middle one
+	prices := []int64{300, 100, 200}
+	i := 0
+	calc := func(time.Time) (*big.Int, error) {
+		price := prices[i%len(prices)]
+		i++
+		return big.NewInt(price), nil
+	}
+	if _, err := h.oracle.Poke(ctx, calc); err != nil {
+		t.Fatal(err)
+	}
+
+	price, err := h.oracle.GetMedianPrice(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(200)) != 0 {
+		t.Errorf("price %s, want 200", price)
+	}
+}
+
+func TestGetOsmPrice(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	h := newHarness(t, 1)
+
+	expect := func(curr int64, next int64) {
+		t.Helper()
+		gotCurr, gotNext, err := h.oracle.GetOsmPrice(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotCurr.Cmp(big.NewInt(curr)) != 0 || gotNext.Cmp(big.NewInt(next)) != 0 {
+			t.Errorf("prices %s, %s, want %d, %d", gotCurr, gotNext, curr, next)
+		}
+	}
+
+	for _, price := range []int64{100, 200} {
+		if _, err := h.oracle.Poke(ctx, constant(price)); err != nil {
+			t.Fatal(err)
+		}
+		h.backend.advance(t, time.Hour)
+		pass, err := h.oracle.OsmPass(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pass {
+			t.Fatal("expected the hop to have elapsed")
+		}
+		if _, err := h.oracle.PokeOsm(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expect(100, 200)
+}
+
+func TestReverts(t *testing.T) {
+	t.Parallel()
+	h := newHarness(t, 1)
+
+	tests := []struct {
+		name   string
+		send   func(ctx context.Context) (*TxResult, error)
+		reason string
+	}{
+		{
+			name: "even bar",
+			send: func(ctx context.Context) (*TxResult, error) {
+				return h.oracle.SetBar(ctx, 2)
+			},
+			reason: "Median/quorum-not-odd-number",
+		},
+		{
+			name: "osm before hop",
+			send: func(ctx context.Context) (*TxResult, error) {
+				return h.oracle.PokeOsm(ctx)
+			},
+			reason: "OSM/not-passed",
+		},
+		{
+			name: "dropped signer",
+			send: func(ctx context.Context) (*TxResult, error) {
+				if _, err := h.oracle.Drop(ctx, crypto.PubkeyToAddress(h.signers[0].PublicKey)); err != nil {
+					return nil, err
+				}
+				return h.oracle.Poke(ctx, constant(100))
+			},
+			reason: "Median/invalid-oracle",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.send(context.Background())
+			var revert *RevertError
+			if !errors.As(err, &revert) {
+				t.Fatalf("got %v, want a revert", err)
+			}
+			if revert.Reason != test.reason {
+				t.Errorf("reason %q, want %q", revert.Reason, test.reason)
+			}
+		})
+	}
+}
+
+// replayBackend records the replayed call and reverts it with data.
+type replayBackend struct {
+	Backend
+	msg   ethereum.CallMsg
+	block *big.Int
+	data  []byte
+}
+
+func (backend *replayBackend) CallContract(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	backend.msg, backend.block = msg, block
+	return nil, rpcRevert(backend.data)
+}
+
+// rpcRevert is the error of a reverted eth_call, carrying its return data.
+type rpcRevert []byte
+
+func (rpcRevert) Error() string               { return "execution reverted" }
+func (rpcRevert) ErrorCode() int              { return 3 }
+func (data rpcRevert) ErrorData() interface{} { return hexutil.Encode(data) }
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	to := common.Address{1}
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := (abi.Arguments{{Type: stringType}}).Pack("Median/stale-message")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		tx   *types.Transaction
+	}{
+		{"legacy", types.NewTx(&types.LegacyTx{To: &to, Gas: 100_000, GasPrice: big.NewInt(7), Value: big.NewInt(1)})},
+		{"dynamic fee", types.NewTx(&types.DynamicFeeTx{To: &to, Gas: 100_000, GasFeeCap: big.NewInt(9), GasTipCap: big.NewInt(2), Value: big.NewInt(1)})},
+	}
+
+	for _, test := range tests {
+		backend := &replayBackend{data: append(append([]byte{}, errorSelector...), data...)}
+		oracle := &Oracle{backend: backend, from: common.Address{2}}
+		revert := oracle.replay(context.Background(), &TxResult{Tx: test.tx, BlockNumber: big.NewInt(10)})
+
+		if revert.Reason != "Median/stale-message" || !revert.Decoded {
+			t.Errorf("%s: reason %q, want the decoded one", test.name, revert.Reason)
+		}
+		// the state the transaction saw, before the other transactions of its block
+		if backend.block == nil || backend.block.Int64() != 9 {
+			t.Errorf("%s: replayed at block %v, want 9", test.name, backend.block)
+		}
+		msg := backend.msg
+		if msg.From != oracle.from || msg.Value.Cmp(test.tx.Value()) != 0 || msg.Gas != test.tx.Gas() {
+			t.Errorf("%s: from %s value %s gas %d, want the transaction ones", test.name, msg.From, msg.Value, msg.Gas)
+		}
+		if test.tx.Type() == types.DynamicFeeTxType {
+			if msg.GasPrice != nil || msg.GasFeeCap.Cmp(test.tx.GasFeeCap()) != 0 || msg.GasTipCap.Cmp(test.tx.GasTipCap()) != 0 {
+				t.Errorf("%s: fees %v %v %v, want the fee and tip caps of the transaction", test.name, msg.GasPrice, msg.GasFeeCap, msg.GasTipCap)
+			}
+		} else if msg.GasPrice.Cmp(test.tx.GasPrice()) != 0 || msg.GasFeeCap != nil {
+			t.Errorf("%s: gas price %v, want %v", test.name, msg.GasPrice, test.tx.GasPrice())
+		}
+	}
+}
+
+func TestAdminRequiresWard(t *testing.T) {
+	t.Parallel()
+	h := newHarness(t, 1)
+
+	stranger := newTestOracle(t, h.backend, newKey(t), Contracts{Osm: h.osm})
+	if _, err := stranger.Lift(context.Background(), common.Address{1}); err == nil {
+		t.Error("expected lift from a non-ward to fail")
+	}
+	if _, err := stranger.StopOsm(context.Background()); err == nil {
+		t.Error("expected stop from a non-ward to fail")
+	}
+}
+
+func TestWatchPrices(t *testing.T) {
+	t.Parallel()
+	h := newHarness(t, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	head, err := h.oracle.BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates := make(chan PriceUpdate)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- h.oracle.WatchPrices(ctx, head+1, 10*time.Millisecond, updates)
+	}()
+
+	poke, err := h.oracle.Poke(ctx, constant(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.backend.advance(t, time.Hour)
+	pokeOsm, err := h.oracle.PokeOsm(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the OSM logs the value it moves to current, which is zero on its first poke
+	want := []struct {
+		contract string
+		price    int64
+		tx       common.Hash
+	}{
+		{MedianContract, 100, poke.Hash()},
+		{OsmContract, 0, pokeOsm.Hash()},
+	}
+	for _, w := range want {
+		select {
+		case update := <-updates:
+			if update.Contract != w.contract || update.Price.Cmp(big.NewInt(w.price)) != 0 || update.TxHash != w.tx {
+				t.Errorf("update %s %s %s, want %s %d %s", update.Contract, update.Price, update.TxHash.Hex(), w.contract, w.price, w.tx.Hex())
+			}
+		case err := <-errc:
+			t.Fatalf("watch stopped: %v", err)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("no %s update", w.contract)
+		}
+	}
+
+	cancel()
+	if err := <-errc; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPokeHistory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	h := newHarness(t, 3)
+
+	start, err := h.oracle.BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poke, err := h.oracle.Poke(ctx, constant(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.oracle.Drop(ctx, crypto.PubkeyToAddress(h.signers[0].PublicKey)); err != nil {
+		t.Fatal(err)
+	}
+	// a fixed gas limit skips the estimation so that the revert is mined
+	observations, err := h.oracle.observe(ctx, constant(200), time.Now(), h.oracle.wat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vals, ages, vs, rs, ss := []*big.Int{}, []*big.Int{}, []uint8{}, [][32]byte{}, [][32]byte{}
+	for _, obs := range observations {
+		vals, ages, vs, rs, ss = append(vals, obs.val), append(ages, obs.age), append(vs, obs.v), append(rs, obs.r), append(ss, obs.s)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(h.owner, big.NewInt(simulatedChainId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.GasLimit = 1_000_000
+	if _, err := h.oracle.median.Transact(auth, "poke", vals, ages, vs, rs, ss); err != nil {
+		t.Fatal(err)
+	}
+	end, err := h.oracle.BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signers := make(map[common.Address]bool)
+	for _, signer := range h.oracle.Signers() {
+		signers[signer] = true
+	}
+
+	for _, test := range []struct {
+		name     string
+		reverted bool
+		pokes    int
+	}{
+		{"logged", false, 1},
+		{"scanned", true, 2},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := h.oracle.PokeHistory(ctx, start, end, test.reverted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != test.pokes {
+				t.Fatalf("%d pokes, want %d", len(records), test.pokes)
+			}
+
+			record := records[0]
+			if record.TxHash != poke.Hash() || record.From != h.oracle.From() || record.Median.Cmp(big.NewInt(100)) != 0 {
+				t.Errorf("poke %s from %s median %s, want %s from %s median 100", record.TxHash.Hex(), record.From.Hex(), record.Median, poke.Hash().Hex(), h.oracle.From().Hex())
+			}
+			if len(record.Observations) != 3 {
+				t.Fatalf("%d observations, want 3", len(record.Observations))
+			}
+			for _, obs := range record.Observations {
+				if !signers[obs.Signer] || obs.Val.Cmp(big.NewInt(100)) != 0 {
+					t.Errorf("observation %s signed by %s", obs.Val, obs.Signer.Hex())
+				}
+			}
+
+			if test.reverted && (records[1].Status != 0 || records[1].Median != nil) {
+				t.Errorf("second poke status %d median %s, want a revert", records[1].Status, records[1].Median)
+			}
+		})
+	}
+}
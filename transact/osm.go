@@ -0,0 +1,62 @@
+package transact
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tgfukuda/test-feed/util"
+)
+
+//errors
+var (
+	errGetZzz  = errors.New("failed to get zzz")
+	errGetHop  = errors.New("failed to get hop")
+	errGetPass = errors.New("failed to check pass")
+)
+
+// HasOsm reports whether the oracle has an OSM in front of the Median.
+func (oracle *Oracle) HasOsm() bool {
+	return oracle.osm != nil
+}
+
+// GetOsmSchedule returns the time of the last OSM poke and the delay between pokes.
+func (oracle *Oracle) GetOsmSchedule(ctx context.Context) (time.Time, time.Duration, error) {
+	if oracle.osm == nil {
+		return time.Time{}, 0, errNoOsm
+	}
+	callOpts := oracle.callOpts(ctx)
+
+	zzz, err := oracle.osm.Zzz(callOpts)
+	if err != nil {
+		return time.Time{}, 0, util.ChainError(errGetZzz, err)
+	}
+
+	hop, err := oracle.osm.Hop(callOpts)
+	if err != nil {
+		return time.Time{}, 0, util.ChainError(errGetHop, err)
+	}
+
+	return time.Unix(int64(zzz), 0), time.Duration(hop) * time.Second, nil
+}
+
+// OsmPass reports whether a hop has elapsed so that OSM accepts a poke.
+func (oracle *Oracle) OsmPass(ctx context.Context) (bool, error) {
+	if oracle.osm == nil {
+		return false, errNoOsm
+	}
+	pass, err := oracle.osm.Pass(oracle.callOpts(ctx))
+	if err != nil {
+		return false, util.ChainError(errGetPass, err)
+	}
+
+	return pass, nil
+}
+
+// PokeOsm moves the current median value into the OSM queue.
+func (oracle *Oracle) PokeOsm(ctx context.Context) (*TxResult, error) {
+	if oracle.osm == nil {
+		return nil, errNoOsm
+	}
+	return oracle.send(ctx, oracle.osm.contract, "poke")
+}
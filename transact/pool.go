@@ -0,0 +1,477 @@
+package transact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+//errors
+var (
+	errNoEndpoint = errors.New("at least one endpoint is required")
+	errNoHealthy  = errors.New("no healthy rpc endpoint")
+)
+
+func errChainMismatch(endpoint string, expected *big.Int, actual *big.Int) error {
+	return fmt.Errorf("%s serves chain %s, expected %s", endpoint, actual, expected)
+}
+
+func errStaleHead(endpoint string, age time.Duration) error {
+	return fmt.Errorf("%s head is %s old", endpoint, age.Truncate(time.Second))
+}
+
+// PoolConfig controls the health checks of the endpoints.
+type PoolConfig struct {
+	ProbeInterval time.Duration // delay between health probes. 0 probes only when no node is healthy
+	MaxBlockAge   time.Duration // a node whose latest block is older is unhealthy. 0 disables
+}
+
+var DefaultPoolConfig = PoolConfig{
+	ProbeInterval: 15 * time.Second,
+	MaxBlockAge:   0,
+}
+
+// conn is a live connection to a node. it is replaced as a whole when the node is dialed again.
+type conn struct {
+	rpc *rpc.Client
+	eth *ethclient.Client
+}
+
+type node struct {
+	endpoint string
+	conn     *conn
+	healthy  bool
+	err      error
+}
+
+// Pool is a Backend spreading over several endpoints of the same chain.
+// Calls go to the first healthy node and move on to the next one when the connection fails,
+// while a background probe checks the chain id and head freshness of every node.
+type Pool struct {
+	config  PoolConfig
+	logger  log.Logger
+	chainId *big.Int
+
+	mu      sync.RWMutex
+	nodes   []*node
+	current int
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Dial connects to every endpoint and starts the health probes.
+// The chain id of the first reachable endpoint is expected from all others.
+func Dial(endpoints []string, config PoolConfig, logger log.Logger) (*Pool, error) {
+	if len(endpoints) == 0 {
+		return nil, errNoEndpoint
+	}
+
+	pool := &Pool{
+		config: config,
+		logger: logger,
+		nodes:  make([]*node, len(endpoints)),
+		quit:   make(chan struct{}),
+	}
+	for i, endpoint := range endpoints {
+		pool.nodes[i] = &node{endpoint: endpoint}
+	}
+
+	pool.probe(context.Background())
+
+	pool.mu.RLock()
+	healthy := pool.healthyLocked()
+	pool.mu.RUnlock()
+	if healthy == nil {
+		pool.Close()
+		return nil, util.ChainError(errConnectingRpc, pool.lastErr())
+	}
+
+	if 0 < config.ProbeInterval {
+		pool.wg.Add(1)
+		go pool.run()
+	}
+
+	return pool, nil
+}
+
+func (pool *Pool) run() {
+	defer pool.wg.Done()
+
+	ticker := time.NewTicker(pool.config.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pool.quit:
+			return
+		case <-ticker.C:
+			pool.probe(context.Background())
+		}
+	}
+}
+
+// probe checks every node and switches to the first healthy one if the current one is not.
+// It stops without changing the state of the remaining nodes once ctx is done.
+func (pool *Pool) probe(ctx context.Context) {
+	for _, n := range pool.snapshot() {
+		err := pool.check(ctx, n)
+		if ctx.Err() != nil {
+			return
+		}
+
+		pool.mu.Lock()
+		if err != nil && (n.healthy || n.err == nil) {
+			pool.logger.Warn("rpc is unhealthy", "endpoint", n.endpoint, "err", err)
+		} else if err == nil && !n.healthy {
+			pool.logger.Info("rpc is healthy", "endpoint", n.endpoint)
+		}
+		n.healthy, n.err = err == nil, err
+		pool.mu.Unlock()
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if !pool.nodes[pool.current].healthy {
+		pool.switchLocked()
+	}
+}
+
+func (pool *Pool) check(ctx context.Context, n *node) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	pool.mu.RLock()
+	c := n.conn
+	pool.mu.RUnlock()
+	if c == nil {
+		client, err := rpc.DialContext(ctx, n.endpoint)
+		if err != nil {
+			return err
+		}
+		c = &conn{client, ethclient.NewClient(client)}
+		pool.mu.Lock()
+		n.conn = c
+		pool.mu.Unlock()
+	}
+	eth := c.eth
+
+	chainId, err := eth.ChainID(ctx)
+	if err != nil {
+		pool.drop(n)
+		return err
+	}
+
+	pool.mu.Lock()
+	if pool.chainId == nil {
+		pool.chainId = chainId
+	}
+	expected := pool.chainId
+	pool.mu.Unlock()
+	if chainId.Cmp(expected) != 0 {
+		return errChainMismatch(n.endpoint, expected, chainId)
+	}
+
+	if 0 < pool.config.MaxBlockAge {
+		head, err := eth.HeaderByNumber(ctx, nil)
+		if err != nil {
+			pool.drop(n)
+			return err
+		}
+		if age := time.Since(time.Unix(int64(head.Time), 0)); pool.config.MaxBlockAge < age {
+			return errStaleHead(n.endpoint, age)
+		}
+	}
+
+	return nil
+}
+
+// drop closes the connection of n so that the next probe dials again. needed for ws and ipc.
+func (pool *Pool) drop(n *node) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if n.conn != nil {
+		n.conn.rpc.Close()
+		n.conn = nil
+	}
+}
+
+func (pool *Pool) snapshot() []*node {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return append([]*node{}, pool.nodes...)
+}
+
+func (pool *Pool) healthyLocked() *node {
+	for i := range pool.nodes {
+		n := pool.nodes[(pool.current+i)%len(pool.nodes)]
+		if n.healthy && n.conn != nil {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// switchLocked moves current to the next healthy node after it.
+func (pool *Pool) switchLocked() {
+	for i := 1; i <= len(pool.nodes); i++ {
+		next := (pool.current + i) % len(pool.nodes)
+		if pool.nodes[next].healthy && pool.nodes[next].conn != nil {
+			if next != pool.current {
+				pool.logger.Warn("rpc failover", "from", pool.nodes[pool.current].endpoint, "to", pool.nodes[next].endpoint)
+			}
+			pool.current = next
+			return
+		}
+	}
+}
+
+func (pool *Pool) lastErr() error {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	for _, n := range pool.nodes {
+		if n.err != nil {
+			return n.err
+		}
+	}
+
+	return errNoHealthy
+}
+
+// markFailed flags n after a failed request and fails over if it was the current node.
+func (pool *Pool) markFailed(n *node, err error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if n.healthy {
+		pool.logger.Warn("rpc failed", "endpoint", n.endpoint, "err", err)
+	}
+	n.healthy, n.err = false, err
+	if pool.nodes[pool.current] == n {
+		pool.switchLocked()
+	}
+}
+
+// Endpoint returns the endpoint currently in use.
+func (pool *Pool) Endpoint() string {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.nodes[pool.current].endpoint
+}
+
+// Close stops the probes and closes every connection.
+func (pool *Pool) Close() {
+	select {
+	case <-pool.quit:
+		return
+	default:
+		close(pool.quit)
+	}
+	pool.wg.Wait()
+
+	for _, n := range pool.snapshot() {
+		pool.drop(n)
+	}
+}
+
+// isTransportErr tells connection failures, worth retrying on another node, from answers of the node itself.
+func isTransportErr(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, ethereum.NotFound) || errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		return false
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+
+	return true
+}
+
+// healthy returns the first healthy node from current and its connection, or nil if there is none.
+func (pool *Pool) healthy() (*node, *conn) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	n := pool.healthyLocked()
+	if n == nil {
+		return nil, nil
+	}
+
+	return n, n.conn
+}
+
+// do runs fn on healthy nodes in order until one answers.
+// When every node has failed since the last probe, they are probed again once instead of waiting for the next probe.
+func do[T any](pool *Pool, ctx context.Context, fn func(*conn) (T, error)) (T, error) {
+	var (
+		zero     T
+		lastErr  error = errNoHealthy
+		reprobed bool
+	)
+	for range pool.snapshot() {
+		n, c := pool.healthy()
+		if n == nil && !reprobed {
+			// the caller waits for the probe, which must end with its ctx
+			pool.probe(ctx)
+			if err := ctx.Err(); err != nil {
+				return zero, err
+			}
+			reprobed = true
+			n, c = pool.healthy()
+		}
+		if n == nil {
+			break
+		}
+
+		result, err := fn(c)
+		if !isTransportErr(ctx, err) {
+			return result, err
+		}
+		pool.markFailed(n, err)
+		lastErr = err
+	}
+
+	return zero, lastErr
+}
+
+func (pool *Pool) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	_, err := do(pool, ctx, func(c *conn) (struct{}, error) {
+		return struct{}{}, c.rpc.CallContext(ctx, result, method, args...)
+	})
+	return err
+}
+
+func (pool *Pool) ChainID(ctx context.Context) (*big.Int, error) {
+	return do(pool, ctx, func(c *conn) (*big.Int, error) { return c.eth.ChainID(ctx) })
+}
+
+func (pool *Pool) BlockNumber(ctx context.Context) (uint64, error) {
+	return do(pool, ctx, func(c *conn) (uint64, error) { return c.eth.BlockNumber(ctx) })
+}
+
+func (pool *Pool) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return do(pool, ctx, func(c *conn) (*types.Block, error) { return c.eth.BlockByHash(ctx, hash) })
+}
+
+func (pool *Pool) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return do(pool, ctx, func(c *conn) (*types.Block, error) { return c.eth.BlockByNumber(ctx, number) })
+}
+
+func (pool *Pool) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return do(pool, ctx, func(c *conn) (*types.Header, error) { return c.eth.HeaderByHash(ctx, hash) })
+}
+
+func (pool *Pool) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return do(pool, ctx, func(c *conn) (*types.Header, error) { return c.eth.HeaderByNumber(ctx, number) })
+}
+
+func (pool *Pool) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	return do(pool, ctx, func(c *conn) (uint, error) { return c.eth.TransactionCount(ctx, blockHash) })
+}
+
+func (pool *Pool) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
+	return do(pool, ctx, func(c *conn) (*types.Transaction, error) { return c.eth.TransactionInBlock(ctx, blockHash, index) })
+}
+
+func (pool *Pool) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	type found struct {
+		tx      *types.Transaction
+		pending bool
+	}
+	result, err := do(pool, ctx, func(c *conn) (found, error) {
+		tx, pending, err := c.eth.TransactionByHash(ctx, hash)
+		return found{tx, pending}, err
+	})
+	return result.tx, result.pending, err
+}
+
+func (pool *Pool) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return do(pool, ctx, func(c *conn) (*types.Receipt, error) { return c.eth.TransactionReceipt(ctx, hash) })
+}
+
+func (pool *Pool) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return do(pool, ctx, func(c *conn) (*big.Int, error) { return c.eth.BalanceAt(ctx, account, blockNumber) })
+}
+
+func (pool *Pool) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return do(pool, ctx, func(c *conn) ([]byte, error) { return c.eth.StorageAt(ctx, account, key, blockNumber) })
+}
+
+func (pool *Pool) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return do(pool, ctx, func(c *conn) ([]byte, error) { return c.eth.CodeAt(ctx, account, blockNumber) })
+}
+
+func (pool *Pool) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return do(pool, ctx, func(c *conn) (uint64, error) { return c.eth.NonceAt(ctx, account, blockNumber) })
+}
+
+func (pool *Pool) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return do(pool, ctx, func(c *conn) ([]byte, error) { return c.eth.PendingCodeAt(ctx, account) })
+}
+
+func (pool *Pool) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return do(pool, ctx, func(c *conn) (uint64, error) { return c.eth.PendingNonceAt(ctx, account) })
+}
+
+func (pool *Pool) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return do(pool, ctx, func(c *conn) ([]byte, error) { return c.eth.CallContract(ctx, call, blockNumber) })
+}
+
+func (pool *Pool) PendingCallContract(ctx context.Context, call ethereum.CallMsg) ([]byte, error) {
+	return do(pool, ctx, func(c *conn) ([]byte, error) { return c.eth.PendingCallContract(ctx, call) })
+}
+
+func (pool *Pool) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return do(pool, ctx, func(c *conn) (*big.Int, error) { return c.eth.SuggestGasPrice(ctx) })
+}
+
+func (pool *Pool) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return do(pool, ctx, func(c *conn) (*big.Int, error) { return c.eth.SuggestGasTipCap(ctx) })
+}
+
+func (pool *Pool) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return do(pool, ctx, func(c *conn) (uint64, error) { return c.eth.EstimateGas(ctx, call) })
+}
+
+// SendTransaction submits tx, treating a node that already knows it from a failed attempt as a success.
+func (pool *Pool) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := do(pool, ctx, func(c *conn) (struct{}, error) {
+		err := c.eth.SendTransaction(ctx, tx)
+		if err != nil && strings.Contains(err.Error(), "already known") {
+			return struct{}{}, nil
+		}
+		return struct{}{}, err
+	})
+	return err
+}
+
+func (pool *Pool) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return do(pool, ctx, func(c *conn) ([]types.Log, error) { return c.eth.FilterLogs(ctx, query) })
+}
+
+func (pool *Pool) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return do(pool, ctx, func(c *conn) (ethereum.Subscription, error) { return c.eth.SubscribeFilterLogs(ctx, query, ch) })
+}
+
+func (pool *Pool) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return do(pool, ctx, func(c *conn) (ethereum.Subscription, error) { return c.eth.SubscribeNewHead(ctx, ch) })
+}
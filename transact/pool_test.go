@@ -0,0 +1,316 @@
+package transact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ethService answers eth_chainId and eth_blockNumber, the block number telling which node answered.
+type ethService struct {
+	chainId int64
+	block   uint64
+}
+
+func (service *ethService) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(service.chainId))
+}
+
+func (service *ethService) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(service.block)
+}
+
+// testNode is a json-rpc server which hangs up every connection while it is down, like a restarting node,
+// and leaves every request unanswered while it hangs, like an unreachable host.
+type testNode struct {
+	*httptest.Server
+	down   int32
+	closed chan struct{}
+}
+
+const (
+	nodeUp int32 = iota
+	nodeDown
+	nodeHanging
+)
+
+func (n *testNode) setDown(down bool) {
+	value := nodeUp
+	if down {
+		value = nodeDown
+	}
+	atomic.StoreInt32(&n.down, value)
+}
+
+func (n *testNode) setHanging() {
+	atomic.StoreInt32(&n.down, nodeHanging)
+}
+
+func newTestNode(t *testing.T, chainId int64, block uint64) *testNode {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &ethService{chainId: chainId, block: block}); err != nil {
+		t.Fatal(err)
+	}
+
+	n := &testNode{closed: make(chan struct{})}
+	n.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.LoadInt32(&n.down) {
+		case nodeDown:
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		case nodeHanging:
+			select {
+			case <-r.Context().Done():
+			case <-n.closed:
+			}
+		default:
+			server.ServeHTTP(w, r)
+		}
+	}))
+	t.Cleanup(func() {
+		close(n.closed)
+		n.Close()
+		server.Stop()
+	})
+
+	return n
+}
+
+// rpcError is an error answered by a node, as opposed to a failed connection.
+type rpcError struct{}
+
+func (rpcError) Error() string  { return "execution reverted" }
+func (rpcError) ErrorCode() int { return 3 }
+
+func TestPoolFailover(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		// chain id of each node, the first one being current. node i answers block i+1
+		chains []int64
+		// nodes taken down then brought back up, and the block expected while they are down and after. 0 expects an error
+		down      []int
+		up        []int
+		probe     bool
+		wantDown  uint64
+		wantAfter uint64
+	}{
+		{
+			name:      "current node killed",
+			chains:    []int64{1, 1},
+			down:      []int{0},
+			wantDown:  2,
+			wantAfter: 2,
+		},
+		{
+			name:      "wrong chain id is skipped",
+			chains:    []int64{1, 2, 1},
+			down:      []int{0},
+			wantDown:  3,
+			wantAfter: 3,
+		},
+		{
+			name:      "probe brings a node back",
+			chains:    []int64{1, 1},
+			down:      []int{0},
+			up:        []int{0},
+			probe:     true,
+			wantDown:  2,
+			wantAfter: 2,
+		},
+		{
+			name:      "every node failed is probed on demand",
+			chains:    []int64{1, 1},
+			down:      []int{0, 1},
+			up:        []int{0},
+			wantDown:  0,
+			wantAfter: 1,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			nodes := make([]*testNode, len(test.chains))
+			endpoints := make([]string, len(test.chains))
+			for i, chainId := range test.chains {
+				nodes[i] = newTestNode(t, chainId, uint64(i+1))
+				endpoints[i] = nodes[i].URL
+			}
+			// no background probe, so that only the calls and the explicit probe change the state
+			pool, err := Dial(endpoints, PoolConfig{}, discard())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer pool.Close()
+
+			expect := func(want uint64) {
+				t.Helper()
+				block, err := pool.BlockNumber(ctx)
+				if want == 0 {
+					if err == nil {
+						t.Errorf("block %d, want an error", block)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if block != want {
+					t.Errorf("block %d, want %d from %s", block, want, endpoints[want-1])
+				}
+			}
+
+			expect(1)
+			for _, i := range test.down {
+				nodes[i].setDown(true)
+			}
+			// each down node fails once before the pool moves on
+			for range test.down {
+				expect(test.wantDown)
+			}
+			for _, i := range test.up {
+				nodes[i].setDown(false)
+			}
+			if test.probe {
+				pool.probe(ctx)
+				pool.mu.RLock()
+				healthy := pool.nodes[test.up[0]].healthy
+				pool.mu.RUnlock()
+				if !healthy {
+					t.Errorf("%s is not healthy after a probe", endpoints[test.up[0]])
+				}
+			}
+			expect(test.wantAfter)
+		})
+	}
+}
+
+func TestPoolProbeBringsBackCurrent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	first, second := newTestNode(t, 1, 1), newTestNode(t, 1, 2)
+	pool, err := Dial([]string{first.URL, second.URL}, PoolConfig{}, discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	first.setDown(true)
+	if _, err := pool.BlockNumber(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if pool.Endpoint() != second.URL {
+		t.Fatalf("endpoint %s, want %s", pool.Endpoint(), second.URL)
+	}
+
+	// once the first node is back and the second goes down, the probe moves back to the first
+	first.setDown(false)
+	second.setDown(true)
+	pool.probe(ctx)
+	if pool.Endpoint() != first.URL {
+		t.Errorf("endpoint %s, want %s", pool.Endpoint(), first.URL)
+	}
+	block, err := pool.BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block != 1 {
+		t.Errorf("block %d, want 1", block)
+	}
+}
+
+func TestPoolProbeEndsWithCaller(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNode(t, 1, 1)
+	pool, err := Dial([]string{n.URL}, PoolConfig{}, discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	n.setDown(true)
+	if _, err := pool.BlockNumber(context.Background()); err == nil {
+		t.Fatal("expected an error from a node down")
+	}
+
+	// the on demand probe waits on the hanging node for the caller only, not for its own timeout
+	n.setHanging()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := pool.BlockNumber(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); 5*time.Second < elapsed {
+		t.Errorf("returned after %s", elapsed)
+	}
+}
+
+func TestDialChainMismatch(t *testing.T) {
+	t.Parallel()
+
+	first, other := newTestNode(t, 1, 1), newTestNode(t, 2, 2)
+	pool, err := Dial([]string{first.URL, other.URL}, PoolConfig{}, discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	if pool.nodes[1].healthy {
+		t.Error("expected the node of another chain to be unhealthy")
+	}
+	if pool.nodes[1].err == nil || pool.nodes[1].err.Error() != errChainMismatch(other.URL, big.NewInt(1), big.NewInt(2)).Error() {
+		t.Errorf("got %v, want a chain mismatch", pool.nodes[1].err)
+	}
+}
+
+func TestIsTransportErr(t *testing.T) {
+	t.Parallel()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"nil", context.Background(), nil, false},
+		{"connection refused", context.Background(), errors.New("dial tcp 127.0.0.1:8545: connect: connection refused"), true},
+		{"http status", context.Background(), rpc.HTTPError{StatusCode: http.StatusBadGateway}, true},
+		{"canceled", canceled, errors.New("context canceled"), false},
+		{"not found", context.Background(), ethereum.NotFound, false},
+		{"no subscriptions", context.Background(), rpc.ErrNotificationsUnsupported, false},
+		{"node answer", context.Background(), rpcError{}, false},
+		{"wrapped node answer", context.Background(), fmt.Errorf("call: %w", rpcError{}), false},
+	}
+
+	for _, test := range tests {
+		if got := isTransportErr(test.ctx, test.err); got != test.want {
+			t.Errorf("%s: got %t, want %t", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package transact
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+// panicReasons names the solidity panic codes
+var panicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assert failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
+
+// DecodeRevert renders the return data of a reverted call.
+// Error(string) and Panic(uint256) are decoded, anything else is shown as hex.
+func DecodeRevert(data []byte) string {
+	reason, _ := decodeRevert(data)
+	return reason
+}
+
+// decodeRevert is DecodeRevert telling whether data was an Error(string) or a Panic(uint256).
+func decodeRevert(data []byte) (string, bool) {
+	switch {
+	case len(data) == 0:
+		return "no reason", false
+	case bytes.HasPrefix(data, errorSelector):
+		reason, err := abi.UnpackRevert(data)
+		if err == nil {
+			return reason, true
+		}
+	case bytes.HasPrefix(data, panicSelector) && len(data) == 36:
+		code := new(big.Int).SetBytes(data[4:])
+		if reason, ok := panicReasons[code.Uint64()]; ok && code.IsUint64() {
+			return fmt.Sprintf("panic: %s (0x%x)", reason, code), true
+		}
+		return fmt.Sprintf("panic: 0x%x", code), true
+	}
+
+	return "unknown reason " + hexutil.Encode(data), false
+}
+
+// revertData extracts the revert return data attached to an rpc error, if any.
+func revertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+
+	encoded, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+
+	data, err := hexutil.Decode(encoded)
+	if err != nil {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// RevertError is returned when a call or transaction is (or would be) reverted by the contract.
+type RevertError struct {
+	Reason  string
+	Data    []byte
+	Decoded bool // Reason was decoded from an Error(string) or a Panic(uint256) rather than made up of hex or an rpc error
+}
+
+func newRevertError(data []byte) *RevertError {
+	reason, decoded := decodeRevert(data)
+	return &RevertError{Reason: reason, Data: data, Decoded: decoded}
+}
+
+func (err *RevertError) Error() string {
+	return "execution reverted: " + err.Reason
+}
+
+// asRevert converts an rpc error carrying revert data into a RevertError.
+func asRevert(err error) (*RevertError, bool) {
+	data, ok := revertData(err)
+	if !ok {
+		return nil, false
+	}
+
+	return newRevertError(data), true
+}
+
+// SetTrace enables debug_traceTransaction as the first source of revert reasons.
+// Nodes without the debug namespace fall back to replaying the call.
+func (oracle *Oracle) SetTrace(enabled bool) {
+	oracle.trace = enabled
+}
+
+// revertReason explains why a mined transaction failed.
+func (oracle *Oracle) revertReason(ctx context.Context, result *TxResult) *RevertError {
+	if oracle.trace {
+		revert, err := oracle.traceRevert(ctx, result.Tx.Hash())
+		if err == nil {
+			return revert
+		}
+		oracle.logger.Warn(errGetStackTrace.Error(), "hash", result.Hash(), "err", err)
+	}
+
+	return oracle.replay(ctx, result)
+}
+
+// replay re-executes the transaction with eth_call on the state of the block before its inclusion to recover the revert data.
+// The state after the inclusion block would include the transactions it raced with, such as the poke of another feeder.
+func (oracle *Oracle) replay(ctx context.Context, result *TxResult) *RevertError {
+	tx := result.Tx
+	msg := ethereum.CallMsg{
+		From:  oracle.from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		msg.GasFeeCap, msg.GasTipCap = tx.GasFeeCap(), tx.GasTipCap()
+	} else {
+		msg.GasPrice = tx.GasPrice()
+	}
+	var parent *big.Int
+	if result.BlockNumber != nil && result.BlockNumber.Sign() > 0 {
+		parent = new(big.Int).Sub(result.BlockNumber, common.Big1)
+	}
+	_, err := oracle.backend.CallContract(ctx, msg, parent)
+	if err == nil {
+		return &RevertError{Reason: "unknown reason. replay did not revert"}
+	}
+	if revert, ok := asRevert(err); ok {
+		return revert
+	}
+
+	return &RevertError{Reason: err.Error()}
+}
+
+func (oracle *Oracle) traceRevert(ctx context.Context, hash common.Hash) (*RevertError, error) {
+	var trace struct {
+		Failed      bool   `json:"failed"`
+		ReturnValue string `json:"returnValue"`
+	}
+	caller, ok := oracle.backend.(rawCaller)
+	if !ok {
+		return nil, errors.New("backend does not support debug_traceTransaction")
+	}
+	if err := caller.CallContext(ctx, &trace, "debug_traceTransaction", hash); err != nil {
+		return nil, err
+	}
+	if !trace.Failed {
+		return nil, errors.New("trace reports no failure")
+	}
+
+	data, err := hex.DecodeString(strings.TrimPrefix(trace.ReturnValue, "0x"))
+	if err != nil {
+		return nil, err
+	}
+
+	return newRevertError(data), nil
+}
@@ -0,0 +1,128 @@
+package transact
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tgfukuda/test-feed/util"
+)
+
+//errors
+var (
+	errNoSigner         = errors.New("at least one signer is required")
+	errGetBar           = errors.New("failed to get bar")
+	errGetOrcl          = errors.New("failed to check feeder")
+	errNotEnoughSigners = errors.New("not enough signers to satisfy bar")
+)
+
+func errSlotTaken(signer common.Address, other common.Address) error {
+	return fmt.Errorf("signer %s uses the same slot 0x%02x as %s", signer.Hex(), signer[0], other.Hex())
+}
+
+func errNotLifted(signer common.Address) error {
+	return fmt.Errorf("signer %s is not lifted on median", signer.Hex())
+}
+
+// observation is a single signed price submitted in a poke.
+type observation struct {
+	val *big.Int
+	age *big.Int
+	v   uint8
+	r   [32]byte
+	s   [32]byte
+}
+
+// SetSigners replaces the keys signing observations.
+// Median accepts a single signer per slot (the first byte of its address), so duplicate slots are rejected.
+func (oracle *Oracle) SetSigners(ctx context.Context, keys ...*ecdsa.PrivateKey) error {
+	if len(keys) == 0 {
+		return errNoSigner
+	}
+
+	slots := make(map[byte]common.Address, len(keys))
+	callOpts := oracle.callOpts(ctx)
+	for _, key := range keys {
+		signer := crypto.PubkeyToAddress(key.PublicKey)
+		if other, ok := slots[signer[0]]; ok {
+			return errSlotTaken(signer, other)
+		}
+		slots[signer[0]] = signer
+
+		lifted, err := oracle.median.Orcl(callOpts, signer)
+		if err != nil {
+			return util.ChainError(errGetOrcl, err)
+		}
+		if lifted.Sign() == 0 {
+			return errNotLifted(signer)
+		}
+	}
+
+	oracle.signers = keys
+
+	return nil
+}
+
+// Signers returns the addresses of the keys signing observations.
+func (oracle *Oracle) Signers() []common.Address {
+	addresses := make([]common.Address, len(oracle.signers))
+	for i, key := range oracle.signers {
+		addresses[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+
+	return addresses
+}
+
+// GetBar returns the number of observations required by the median.
+func (oracle *Oracle) GetBar(ctx context.Context) (*big.Int, error) {
+	bar, err := oracle.median.Bar(oracle.callOpts(ctx))
+	if err != nil {
+		return nil, util.ChainError(errGetBar, err)
+	}
+
+	return bar, nil
+}
+
+// observe lets bar signers sign their own observation at ts and returns them sorted by value as Median requires.
+func (oracle *Oracle) observe(ctx context.Context, calc Calculator, ts time.Time, wat string) ([]observation, error) {
+	bar, err := oracle.GetBar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !bar.IsInt64() || int64(len(oracle.signers)) < bar.Int64() || bar.Sign() == 0 {
+		return nil, fmt.Errorf("%w: bar %s, signers %d", errNotEnoughSigners, bar, len(oracle.signers))
+	}
+
+	observations := make([]observation, bar.Int64())
+	for i := range observations {
+		price, err := calc(ts)
+		if err != nil {
+			return nil, util.ChainError(errCalcPrice, err)
+		}
+
+		r, s, v, err := Sign(oracle.signers[i], Prefix(Hash(price, ts, wat)))
+		if err != nil {
+			return nil, err
+		}
+
+		observations[i] = observation{
+			val: price,
+			age: big.NewInt(ts.Unix()),
+			v:   v,
+			r:   *r,
+			s:   *s,
+		}
+	}
+
+	sort.SliceStable(observations, func(i, j int) bool {
+		return observations[i].val.Cmp(observations[j].val) < 0
+	})
+
+	return observations, nil
+}
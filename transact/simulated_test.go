@@ -0,0 +1,177 @@
+package transact
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// simulatedChainId is reported by the simulated backend, which has no eth_chainId of its own.
+const simulatedChainId = 1337
+
+// simulated adapts the go-ethereum simulated backend to Backend.
+// Every transaction is mined in its own block as soon as it is sent.
+type simulated struct {
+	*backends.SimulatedBackend
+}
+
+func (backend *simulated) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(simulatedChainId), nil
+}
+
+func (backend *simulated) BlockNumber(ctx context.Context) (uint64, error) {
+	return backend.Blockchain().CurrentBlock().NumberU64(), nil
+}
+
+func (backend *simulated) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := backend.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	backend.Commit()
+
+	return nil
+}
+
+// advance moves the chain clock forward by d and mines an empty block.
+func (backend *simulated) advance(t *testing.T, d time.Duration) {
+	t.Helper()
+	if err := backend.AdjustTime(d); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+}
+
+// harness is a Median and an OSM deployed on a fresh simulated chain.
+// The owner is a ward of both, kissed on both and lifted on the Median along with the signers.
+type harness struct {
+	backend *simulated
+	owner   *ecdsa.PrivateKey
+	signers []*ecdsa.PrivateKey
+	median  common.Address
+	osm     common.Address
+	oracle  *Oracle
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+// newSigners returns n keys using distinct Median slots.
+func newSigners(t *testing.T, n int) []*ecdsa.PrivateKey {
+	t.Helper()
+	slots := make(map[byte]bool, n)
+	keys := make([]*ecdsa.PrivateKey, 0, n)
+	for len(keys) < n {
+		key := newKey(t)
+		slot := crypto.PubkeyToAddress(key.PublicKey)[0]
+		if slots[slot] {
+			continue
+		}
+		slots[slot] = true
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func discard() log.Logger {
+	logger := log.New()
+	logger.SetHandler(log.DiscardHandler())
+
+	return logger
+}
+
+func newTestOracle(t *testing.T, backend Backend, key *ecdsa.PrivateKey, contracts Contracts) *Oracle {
+	t.Helper()
+	oracle, err := New(context.Background(), backend, key, contracts, "", "", discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	oracle.SetConfirmConfig(ConfirmConfig{Timeout: time.Minute})
+
+	return oracle
+}
+
+// newHarness deploys the contracts with bar and as many signers, which sign the pokes of the returned oracle.
+// It fails without the solc output of makerdao/median and makerdao/osm embedded in the contracts package (see deploy-oracle.sh).
+// Each transaction waits for one poll interval, so the harness is skipped in short mode.
+func newHarness(t *testing.T, bar int) *harness {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("sends transactions on a simulated chain")
+	}
+	ctx := context.Background()
+
+	owner := newKey(t)
+	signers := newSigners(t, bar)
+	backend := &simulated{backends.NewSimulatedBackend(core.GenesisAlloc{
+		crypto.PubkeyToAddress(owner.PublicKey): {Balance: new(big.Int).Lsh(common.Big1, 100)},
+	}, 30_000_000)}
+	t.Cleanup(func() { backend.Close() })
+
+	deployer, err := NewAccount(backend, owner, discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployer.SetConfirmConfig(ConfirmConfig{Timeout: time.Minute})
+	median, _, err := deployer.DeployMedian(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	osm, _, err := deployer.DeployOSM(ctx, median)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oracle := newTestOracle(t, backend, owner, Contracts{Osm: osm, Median: median})
+	feeders := []common.Address{oracle.From()}
+	for _, signer := range signers {
+		feeders = append(feeders, crypto.PubkeyToAddress(signer.PublicKey))
+	}
+	if _, err := oracle.Lift(ctx, feeders...); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := oracle.SetBar(ctx, uint64(bar)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := oracle.KissMedian(ctx, osm, oracle.From()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := oracle.KissOsm(ctx, oracle.From()); err != nil {
+		t.Fatal(err)
+	}
+	if err := oracle.SetSigners(ctx, signers...); err != nil {
+		t.Fatal(err)
+	}
+
+	return &harness{
+		backend: backend,
+		owner:   owner,
+		signers: signers,
+		median:  median,
+		osm:     osm,
+		oracle:  oracle,
+	}
+}
+
+// constant is a Calculator always returning price.
+func constant(price int64) Calculator {
+	return func(time.Time) (*big.Int, error) {
+		return big.NewInt(price), nil
+	}
+}
@@ -1,12 +1,11 @@
 package transact
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
-	"encoding/hex"
 	"errors"
 	"fmt"
-	"log"
 	"math/big"
 	"os"
 	"time"
@@ -17,18 +16,27 @@ import (
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/tgfukuda/test-feed/contracts"
 	"github.com/tgfukuda/test-feed/util"
 )
 
 type Oracle struct {
-	client  *rpc.Client
+	backend Backend
 	privKey *ecdsa.PrivateKey
+	signers []*ecdsa.PrivateKey
 	from    common.Address // ETH_FROM
-	osm     *bind.BoundContract
-	median  *bind.BoundContract
-	logger  *log.Logger
+	osm     *osmContract
+	median  *medianContract
+	wat     string
+	fee     FeeConfig
+	gas     GasConfig
+	trace   bool
+	logger  log.Logger
+
+	nonces        *NonceManager
+	nonceConfig   NonceConfig
+	confirmConfig ConfirmConfig
 }
 
 //errors
@@ -44,25 +52,56 @@ var (
 	errTransactObj   = errors.New("failed to get transact object")
 	errGetBlock      = errors.New("failed to get block")
 	errGetStackTrace = errors.New("failed to get stack trace")
+	errCalcPrice     = errors.New("failed to calculate price")
+	errGetWat        = errors.New("failed to get wat")
+	errGetAge        = errors.New("failed to get age")
+	errNoContract    = errors.New("either OSM or Median address is required")
+	errNoOsm         = errors.New("no OSM is configured")
+	errGetBalance    = errors.New("failed to get balance")
+	errGetBud        = errors.New("failed to get buds")
 )
 
 func errAbiPath(path string) error {
 	return fmt.Errorf("failed to open abi file %s", path)
 }
 
+func errWatMismatch(expected string, actual string) error {
+	return fmt.Errorf("wat mismatch: configured %q but median expects %q", expected, actual)
+}
+
+func errNotBud(address common.Address, from common.Address) error {
+	return fmt.Errorf("%s is not a bud of the Median at %s and cannot read its value", from.Hex(), address.Hex())
+}
+
+func errInvalidWat(wat string) error {
+	return fmt.Errorf("wat %q cannot be signed as bytes32", wat)
+}
+
 var warnPokeOnce = "osm has no current value. `poke` may have been called only once"
 
 var (
 	Zero = big.NewInt(0)
 )
 
-func New(endpoint string, privateKey *ecdsa.PrivateKey, osm string, osmAbi string, medianAbi string, logger *log.Logger) (*Oracle, error) {
-	oracle, err := initOsm(endpoint, privateKey, osm, osmAbi, logger)
+// Contracts locates the oracle.
+// Median is read from OSM src() when zero, and OSM may be zero to work with a Median alone.
+type Contracts struct {
+	Osm    common.Address
+	Median common.Address
+}
+
+func New(ctx context.Context, backend Backend, privateKey *ecdsa.PrivateKey, contracts Contracts, osmAbi string, medianAbi string, logger log.Logger) (*Oracle, error) {
+	oracle, err := NewAccount(backend, privateKey, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	err = oracle.initOsm(contracts.Osm, osmAbi)
 	if err != nil {
 		return nil, err
 	}
 
-	err = oracle.initMedian(nil, medianAbi)
+	err = oracle.initMedian(ctx, contracts.Median, medianAbi)
 	if err != nil {
 		return nil, err
 	}
@@ -70,18 +109,69 @@ func New(endpoint string, privateKey *ecdsa.PrivateKey, osm string, osmAbi strin
 	return oracle, nil
 }
 
-func (oracle *Oracle) Delete() error {
-	oracle.client.Close()
-	oracle.logger.Printf("disconnecting rpc...\n")
-	return nil
+// From returns the account sending the transactions.
+func (oracle *Oracle) From() common.Address {
+	return oracle.from
+}
+
+// Contracts returns the addresses in use, with the Median resolved from the OSM if it was not given.
+func (oracle *Oracle) Contracts() Contracts {
+	var contracts Contracts
+	if oracle.osm != nil {
+		contracts.Osm = oracle.osm.address
+	}
+	if oracle.median != nil {
+		contracts.Median = oracle.median.address
+	}
+
+	return contracts
+}
+
+// Balance returns the balance of the sending account in wei.
+func (oracle *Oracle) Balance(ctx context.Context) (*big.Int, error) {
+	balance, err := oracle.backend.BalanceAt(ctx, oracle.from, nil)
+	if err != nil {
+		return nil, util.ChainError(errGetBalance, err)
+	}
+
+	return balance, nil
+}
+
+// Nonce returns the next nonce of the sending account, counting pending transactions.
+func (oracle *Oracle) Nonce(ctx context.Context) (uint64, error) {
+	nonce, err := oracle.backend.PendingNonceAt(ctx, oracle.from)
+	if err != nil {
+		return 0, util.ChainError(errCalcNonce, err)
+	}
+
+	return nonce, nil
 }
 
-func initOsm(endpoint string, privateKey *ecdsa.PrivateKey, osm string, osmAbi string, logger *log.Logger) (*Oracle, error) {
-	client, err := rpc.DialHTTP(endpoint)
+// BlockNumber returns the number of the latest block.
+func (oracle *Oracle) BlockNumber(ctx context.Context) (uint64, error) {
+	number, err := oracle.backend.BlockNumber(ctx)
 	if err != nil {
-		return nil, util.ChainError(errConnectingRpc, err)
+		return 0, util.ChainError(errGetBlock, err)
 	}
 
+	return number, nil
+}
+
+// Delete closes the connection.
+func (oracle *Oracle) Delete() error {
+	oracle.logger.Debug("disconnecting rpc")
+	switch closer := oracle.backend.(type) {
+	case interface{ Close() }:
+		closer.Close()
+	case interface{ Close() error }:
+		return closer.Close()
+	}
+	return nil
+}
+
+// NewAccount returns an oracle sending from privateKey without contracts.
+// Only the deployments can be sent from it.
+func NewAccount(backend Backend, privateKey *ecdsa.PrivateKey, logger log.Logger) (*Oracle, error) {
 	publicKey := privateKey.Public()
 	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
 	if !ok {
@@ -90,126 +180,225 @@ func initOsm(endpoint string, privateKey *ecdsa.PrivateKey, osm string, osmAbi s
 
 	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
 
-	address := common.HexToAddress(osm)
-
-	contract, err := getContract(address, osmAbi, client)
-	if err != nil {
-		return nil, err
-	}
-
-	logger.Printf("[INFO] OSM address: %s", address.Hex())
-
 	return &Oracle{
-		client:  client,
+		backend: backend,
 		privKey: privateKey,
+		signers: []*ecdsa.PrivateKey{privateKey},
 		from:    fromAddress,
-		osm:     contract,
+		osm:     nil,
 		median:  nil,
+		gas:     DefaultGasConfig,
 		logger:  logger,
+
+		nonces:        NewNonceManager(),
+		nonceConfig:   DefaultNonceConfig,
+		confirmConfig: DefaultConfirmConfig,
 	}, nil
 }
 
-func getContract(address common.Address, abiPath string, client *rpc.Client) (*bind.BoundContract, error) {
-	abiFile, err := os.Open(abiPath)
+func (oracle *Oracle) initOsm(address common.Address, osmAbi string) error {
+	if address == (common.Address{}) {
+		return nil
+	}
+
+	parsed, err := loadAbi(osmAbi, contracts.OSMMetaData)
 	if err != nil {
-		return nil, util.ChainError(errAbiPath(abiPath), err)
+		return err
+	}
+	binding, bound := contracts.BindOSM(address, parsed, oracle.backend)
+	oracle.osm = &osmContract{&contract{bound, address, parsed}, &binding.OSMCaller, &binding.OSMFilterer}
+
+	oracle.logger.Info("OSM", "address", address)
+
+	return nil
+}
+
+// contract keeps the parsed abi next to the binding so that calldata can be built for estimation
+type contract struct {
+	*bind.BoundContract
+	address common.Address
+	abi     abi.ABI
+}
+
+// medianContract reads a Median through the typed binding and transacts through contract.
+type medianContract struct {
+	*contract
+	*contracts.MedianCaller
+	*contracts.MedianFilterer
+}
+
+// osmContract reads an OSM through the typed binding and transacts through contract.
+type osmContract struct {
+	*contract
+	*contracts.OSMCaller
+	*contracts.OSMFilterer
+}
+
+// loadAbi parses the abi file at path, or takes the embedded one if path is empty.
+func loadAbi(path string, embedded *bind.MetaData) (abi.ABI, error) {
+	if path == "" {
+		parsed, err := embedded.GetAbi()
+		if err != nil {
+			return abi.ABI{}, util.ChainError(errParseAbi, err)
+		}
+		return *parsed, nil
+	}
+
+	abiFile, err := os.Open(path)
+	if err != nil {
+		return abi.ABI{}, util.ChainError(errAbiPath(path), err)
 	}
 	defer abiFile.Close()
 
 	parsed, err := abi.JSON(abiFile)
 	if err != nil {
-		return nil, util.ChainError(errParseAbi, err)
+		return abi.ABI{}, util.ChainError(errParseAbi, err)
 	}
-	ethClient := ethclient.NewClient(client)
-	contract := bind.NewBoundContract(address, parsed, ethClient, ethClient, ethClient)
 
-	return contract, nil
+	return parsed, nil
 }
 
-func callMethod1[T interface{}](contract *bind.BoundContract, callOpts *bind.CallOpts, method string, args ...interface{}) (*T, error) {
-	var result []interface{}
-	err := contract.Call(callOpts, &result, method, args...)
-	if err != nil {
-		return nil, err
+// callOpts reads the pending state as the sender
+func (oracle *Oracle) callOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{Pending: true, From: oracle.from, Context: ctx}
+}
+
+func (oracle *Oracle) initMedian(ctx context.Context, address common.Address, medianAbi string) error {
+	if oracle.osm != nil {
+		src, err := oracle.osm.Src(oracle.callOpts(ctx))
+		if err != nil {
+			return util.ChainError(errGetMedian, err)
+		}
+		if address == (common.Address{}) {
+			address = src
+		} else if address != src {
+			oracle.logger.Warn("OSM reads from another Median", "src", src, "median", address)
+		}
+	}
+	if address == (common.Address{}) {
+		return errNoContract
 	}
 
-	conversion, ok := result[0].(T)
-	if !ok {
-		return nil, util.ErrCast
+	parsed, err := loadAbi(medianAbi, contracts.MedianMetaData)
+	if err != nil {
+		return err
 	}
+	binding, bound := contracts.BindMedian(address, parsed, oracle.backend)
 
-	return &conversion, nil
-}
+	oracle.logger.Info("Median", "address", address)
+
+	oracle.median = &medianContract{&contract{bound, address, parsed}, &binding.MedianCaller, &binding.MedianFilterer}
 
-func callMethod2[T interface{}, S interface{}](contract *bind.BoundContract, callOpts *bind.CallOpts, method string, args ...interface{}) (*T, *S, error) {
-	var result []interface{}
-	err := contract.Call(callOpts, &result, method, args...)
+	wat, err := oracle.median.Wat(oracle.callOpts(ctx))
 	if err != nil {
-		return nil, nil, err
+		return util.ChainError(errGetWat, err)
 	}
+	oracle.wat = string(bytes.TrimRight(wat[:], "\x00"))
 
-	conversion1, ok := result[0].(T)
-	if !ok {
-		return nil, nil, util.ErrCast
+	oracle.logger.Info("Median wat", "wat", oracle.wat)
+
+	return nil
+}
+
+// WatBytes pads wat into the bytes32 form used in the signed message.
+func WatBytes(wat string) (out [32]byte) {
+	copy(out[:], wat)
+	return out
+}
+
+// Wat returns the asset label of the median, which every observation is signed with.
+func (oracle *Oracle) Wat() string {
+	return oracle.wat
+}
+
+// ValidateWat ensures the configured label is the one the median verifies signatures against.
+func (oracle *Oracle) ValidateWat(wat string) error {
+	if 32 < len(wat) {
+		return errInvalidWat(wat)
+	}
+	if WatBytes(wat) != WatBytes(oracle.wat) {
+		return errWatMismatch(wat, oracle.wat)
 	}
 
-	conversion2, ok := result[1].(S)
-	if !ok {
-		return nil, nil, util.ErrCast
+	return nil
+}
+
+func (oracle *Oracle) GetMedianPrice(ctx context.Context) (*big.Int, error) {
+	price, valid, err := oracle.median.Peek(oracle.callOpts(ctx))
+	if err != nil {
+		return Zero, err
+	}
+	if !valid {
+		return Zero, errInvalidPrice
 	}
 
-	return &conversion1, &conversion2, nil
+	return price, nil
 }
 
-func (oracle *Oracle) initMedian(callOpts *bind.CallOpts, medianAbi string) error {
-	address, err := callMethod1[common.Address](oracle.osm, &bind.CallOpts{Pending: true, From: oracle.from}, "src")
+// GetMedianState returns the current median value and the time it was last updated.
+// The price is zero when the median has not been poked yet.
+func (oracle *Oracle) GetMedianState(ctx context.Context) (*big.Int, time.Time, error) {
+	age, err := oracle.GetMedianAge(ctx)
 	if err != nil {
-		return util.ChainError(errGetMedian, err)
+		return Zero, time.Time{}, err
 	}
 
-	contract, err := getContract(*address, medianAbi, oracle.client)
+	price, valid, err := oracle.median.Peek(oracle.callOpts(ctx))
 	if err != nil {
-		return err
+		return Zero, time.Time{}, err
+	}
+	if !valid {
+		return Zero, age, nil
 	}
 
-	oracle.logger.Printf("[INFO] Median address: %s", address.Hex())
+	return price, age, nil
+}
 
-	oracle.median = contract
+// RequireMedianBud fails unless the sender is kissed on the median, which peek needs.
+func (oracle *Oracle) RequireMedianBud(ctx context.Context) error {
+	bud, err := oracle.median.Bud(oracle.callOpts(ctx), oracle.from)
+	if err != nil {
+		return util.ChainError(errGetBud, err)
+	}
+	if bud.Cmp(common.Big1) != 0 {
+		return errNotBud(oracle.median.address, oracle.from)
+	}
 
 	return nil
 }
 
-func (oracle *Oracle) GetMedianPrice() (*big.Int, error) {
-	price, valid, err := callMethod2[*big.Int, bool](oracle.median, &bind.CallOpts{Pending: true, From: oracle.from}, "peek")
+// GetMedianAge returns the time the median was last updated. Unlike the value, it can be read without a kiss.
+func (oracle *Oracle) GetMedianAge(ctx context.Context) (time.Time, error) {
+	age, err := oracle.median.Age(oracle.callOpts(ctx))
 	if err != nil {
-		return Zero, err
-	}
-	if !*valid {
-		return Zero, errInvalidPrice
+		return time.Time{}, util.ChainError(errGetAge, err)
 	}
 
-	return *price, nil
+	return time.Unix(int64(age), 0), nil
 }
 
-func (oracle *Oracle) GetOsmPrice() (*big.Int, *big.Int, error) {
-	callOpts := &bind.CallOpts{Pending: true, From: oracle.from}
+func (oracle *Oracle) GetOsmPrice(ctx context.Context) (*big.Int, *big.Int, error) {
+	if oracle.osm == nil {
+		return Zero, Zero, errNoOsm
+	}
+	callOpts := oracle.callOpts(ctx)
 
-	next_, valid, err := callMethod2[[32]byte, bool](oracle.osm, callOpts, "peep")
+	next_, valid, err := oracle.osm.Peep(callOpts)
 	if err != nil {
 		return Zero, Zero, err
 	}
-	if !*valid {
+	if !valid {
 		return Zero, Zero, errInvalidPrice
 	}
 
 	next := new(big.Int).SetBytes(next_[:])
 
-	curr_, valid, err := callMethod2[[32]byte, bool](oracle.osm, callOpts, "peek")
+	curr_, valid, err := oracle.osm.Peek(callOpts)
 	if err != nil {
 		return Zero, next, err
 	}
-	if !*valid {
-		oracle.logger.Printf(warnPokeOnce)
+	if !valid {
+		oracle.logger.Warn(warnPokeOnce)
 		return Zero, next, nil
 	}
 	curr := new(big.Int).SetBytes(curr_[:])
@@ -217,26 +406,51 @@ func (oracle *Oracle) GetOsmPrice() (*big.Int, *big.Int, error) {
 	return curr, next, nil
 }
 
-type Calculator func(ts time.Time) *big.Int
+type Calculator func(ts time.Time) (*big.Int, error)
 
-type TxResult struct {
-	*types.Transaction
+type minerResult struct {
+	*TxResult
 	error
 }
 
-func (oracle *Oracle) Poke(calc Calculator) (*types.Transaction, error) {
-	ethClient := ethclient.NewClient(oracle.client)
-	nonce, err := ethClient.PendingNonceAt(context.Background(), oracle.from)
+func (oracle *Oracle) Poke(ctx context.Context, calc Calculator) (*TxResult, error) {
+	observations, err := oracle.observe(ctx, calc, time.Now(), oracle.wat)
 	if err != nil {
-		return nil, util.ChainError(errCalcNonce, err)
+		return nil, err
+	}
+
+	vals := make([]*big.Int, len(observations))
+	ages := make([]*big.Int, len(observations))
+	vs := make([]uint8, len(observations))
+	rs := make([][32]byte, len(observations))
+	ss := make([][32]byte, len(observations))
+	for i, obs := range observations {
+		vals[i], ages[i], vs[i], rs[i], ss[i] = math.U256(obs.val), math.U256(obs.age), obs.v, obs.r, obs.s
 	}
 
-	gasPrice, err := ethClient.SuggestGasPrice(context.Background())
+	return oracle.send(ctx, oracle.median.contract, "poke", vals, ages, vs, rs, ss)
+}
+
+// message is the content of a transaction, a method call or a contract creation when to is nil.
+type message struct {
+	name string // method name for logs and errors
+	to   *common.Address
+	data []byte
+}
+
+// send builds, signs and submits a transaction calling method on contract, then waits until it is mined.
+func (oracle *Oracle) send(ctx context.Context, contract *contract, method string, args ...interface{}) (*TxResult, error) {
+	data, err := contract.abi.Pack(method, args...)
 	if err != nil {
-		return nil, util.ChainError(errCalcGas, err)
+		return nil, util.ChainError(errPackCall, err)
 	}
 
-	chainId, err := ethClient.ChainID(context.Background())
+	return oracle.sendMessage(ctx, message{name: method, to: &contract.address, data: data})
+}
+
+// sendMessage is send for an encoded message.
+func (oracle *Oracle) sendMessage(ctx context.Context, msg message) (*TxResult, error) {
+	chainId, err := oracle.backend.ChainID(ctx)
 	if err != nil {
 		return nil, util.ChainError(errChainId, err)
 	}
@@ -245,96 +459,100 @@ func (oracle *Oracle) Poke(calc Calculator) (*types.Transaction, error) {
 	if err != nil {
 		return nil, util.ChainError(errTransactObj, err)
 	}
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)      // in wei
-	auth.GasLimit = uint64(7000000) // in units
-	auth.GasPrice = gasPrice
-
-	now := time.Now()
-
-	val, age, wat := calc(now), now, "ethjpy"
-	var watBytes []byte = make([]byte, 32)
-	copy(watBytes, wat)
+	auth.Value = big.NewInt(0) // in wei
+	auth.Context = ctx
 
-	hash := Prefix(Hash(val, age, wat))
+	head, err := oracle.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, util.ChainError(errGetBlock, err)
+	}
+	if err := oracle.applyFees(ctx, head, auth); err != nil {
+		return nil, err
+	}
+	if err := oracle.estimateGas(ctx, head, auth, msg); err != nil {
+		return nil, err
+	}
 
-	r, s, v, err := Sign(oracle.privKey, hash)
+	nonce, err := oracle.nonces.acquire(ctx, oracle.backend, oracle.from)
 	if err != nil {
 		return nil, err
 	}
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+
+	// the wait for inclusion is bounded by the confirmation timeout on top of the caller's context
+	wait := ctx
+	if 0 < oracle.confirmConfig.Timeout {
+		var cancel context.CancelFunc
+		wait, cancel = context.WithTimeout(ctx, oracle.confirmConfig.Timeout)
+		defer cancel()
+	}
 
-	miner := make(chan TxResult)
+	miner := make(chan minerResult)
 
 	go func() {
 		defer close(miner)
-		tx, err := oracle.median.Transact(auth, "poke",
-			[]*big.Int{math.U256(val)},
-			[]*big.Int{math.U256(big.NewInt(age.Unix()))},
-			[]uint8{v},
-			[][32]byte{*r},
-			[][32]byte{*s},
-		)
+		tx, err := oracle.submit(wait, auth, msg)
 		if err != nil {
-			miner <- TxResult{nil, err}
+			miner <- minerResult{unmined(tx), err}
 			return
 		}
-		oracle.logger.Writer().Write([]byte(oracle.logger.Prefix() + "sending transaction..."))
-		for pending := true; pending; _, pending, _ = ethClient.TransactionByHash(context.Background(), tx.Hash()) {
-			oracle.logger.Writer().Write([]byte("."))
-			time.Sleep(time.Duration(3000) * time.Microsecond)
-		}
-		oracle.logger.Writer().Write([]byte("\n"))
-		block, err := ethClient.BlockByNumber(context.Background(), nil)
+		result, err := oracle.confirm(wait, tx)
 		if err != nil {
-			miner <- TxResult{nil, util.ChainError(errGetBlock, err)}
+			miner <- minerResult{unmined(tx), err}
 			return
 		}
-		blockhash := block.Hash()
-		oracle.logger.Printf("included in %s\n", blockhash.Hex())
-		var trace interface{}
-		err = oracle.client.Call(&trace, "debug_traceTransaction", tx.Hash())
-		if err != nil {
-			miner <- TxResult{tx, util.ChainError(errGetStackTrace, err)}
-			return
-		}
-		receipt, err := ethClient.TransactionReceipt(context.Background(), tx.Hash())
-		if err != nil {
-			miner <- TxResult{tx, err}
-		}
-		rec, err := receipt.MarshalJSON()
-		if err != nil {
-			miner <- TxResult{tx, err}
+		oracle.logger.Info("transaction mined", "method", msg.name, "hash", result.Hash(), "block", result.BlockNumber,
+			"gasUsed", result.Receipt.GasUsed, "status", result.Receipt.Status)
+		if rec, err := result.Receipt.MarshalJSON(); err == nil {
+			oracle.logger.Trace("receipt", "hash", result.Hash(), "receipt", string(rec))
 		}
-		oracle.logger.Printf("%s\n", rec)
-		execResult, ok := trace.(map[string]interface{})
-		if !ok {
-			miner <- TxResult{tx, util.ErrCast}
+		if result.Receipt.Status == types.ReceiptStatusFailed {
+			oracle.logger.Warn("execution reverted", "method", msg.name, "hash", result.Hash())
+			miner <- minerResult{result, oracle.revertReason(ctx, result)}
 			return
 		}
-		isFailue, ok := execResult["failed"].(bool)
-		if !ok {
-			miner <- TxResult{tx, util.ErrCast}
-			return
-		} else {
-			if isFailue {
-				oracle.logger.Println("[INFO] execution reverted")
-				reasonRaw, _ := execResult["returnValue"].(string)
-				reason, _ := hex.DecodeString(reasonRaw)
-				miner <- TxResult{tx, errors.New(string(reason))}
-				return
-			}
-		}
-		miner <- TxResult{tx, err}
+		miner <- minerResult{result, nil}
 	}()
 
+	mined := <-miner
+	if mined.error != nil {
+		return mined.TxResult, mined.error
+	}
+
+	return mined.TxResult, nil
+}
+
+// transact signs msg with the fees, gas limit and nonce of auth and sends it.
+func (oracle *Oracle) transact(ctx context.Context, auth *bind.TransactOpts, msg message) (*types.Transaction, error) {
+	var data types.TxData
+	if auth.GasPrice != nil {
+		data = &types.LegacyTx{
+			Nonce:    auth.Nonce.Uint64(),
+			GasPrice: auth.GasPrice,
+			Gas:      auth.GasLimit,
+			To:       msg.to,
+			Value:    auth.Value,
+			Data:     msg.data,
+		}
+	} else {
+		data = &types.DynamicFeeTx{
+			Nonce:     auth.Nonce.Uint64(),
+			GasTipCap: auth.GasTipCap,
+			GasFeeCap: auth.GasFeeCap,
+			Gas:       auth.GasLimit,
+			To:        msg.to,
+			Value:     auth.Value,
+			Data:      msg.data,
+		}
+	}
+
+	tx, err := auth.Signer(auth.From, types.NewTx(data))
 	if err != nil {
 		return nil, err
 	}
-
-	result := <-miner
-	if result.error != nil {
-		return result.Transaction, result.error
+	if err := oracle.backend.SendTransaction(ctx, tx); err != nil {
+		return nil, err
 	}
 
-	return result.Transaction, nil
+	return tx, nil
 }